@@ -0,0 +1,145 @@
+// Command configcrypt encrypts and decrypts config values for config.WithDecrypter.
+//
+// Generate a key once and keep it out of the repository:
+//
+//	configcrypt keygen -o config.key
+//
+// Then encrypt the secrets and commit the output in .env files, e.g. DB_PASSWORD=enc:v1:...:
+//
+//	configcrypt encrypt -key-file config.key 's3cr3t'
+//	echo 's3cr3t' | configcrypt encrypt -key-env CONFIG_KEY
+//	configcrypt decrypt -key-file config.key 'enc:v1:...'
+//
+// When the value is not given as an argument, it is read from stdin without the trailing newline,
+// so it doesn't end up in the shell history.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pushkar-anand/build-with-go/config"
+)
+
+const defaultKeyEnv = "CONFIG_KEY"
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, "configcrypt:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: configcrypt keygen|encrypt|decrypt [flags] [value]")
+	}
+
+	switch args[0] {
+	case "keygen":
+		return keygen(args[1:], stdout)
+	case "encrypt", "decrypt":
+		return crypt(args[0], args[1:], stdin, stdout)
+	default:
+		return fmt.Errorf("unknown command %q, use keygen, encrypt or decrypt", args[0])
+	}
+}
+
+func keygen(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("configcrypt keygen", flag.ContinueOnError)
+	output := fs.String("o", "", "key file to create, defaults to stdout")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	key, err := config.GenerateAESGCMKey()
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = fmt.Fprintln(stdout, key)
+		return err
+	}
+
+	// The key file must not be overwritten by accident, the values encrypted with it would be lost.
+	f, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating key file: %w", err)
+	}
+
+	_, err = fmt.Fprintln(f, key)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("error writing key file: %w", err)
+	}
+
+	return f.Close()
+}
+
+func crypt(command string, args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("configcrypt "+command, flag.ContinueOnError)
+	keyFile := fs.String("key-file", "", "file with the base64 encoded key")
+	keyEnv := fs.String("key-env", defaultKeyEnv, "environment variable with the base64 encoded key, used without -key-file")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	var a *config.AESGCM
+	if *keyFile != "" {
+		a, err = config.NewAESGCMFromFile(*keyFile)
+	} else {
+		a, err = config.NewAESGCMFromEnv(*keyEnv)
+	}
+	if err != nil {
+		return err
+	}
+
+	value, err := readValue(fs.Args(), stdin)
+	if err != nil {
+		return err
+	}
+
+	var out string
+	if command == "encrypt" {
+		out, err = a.Encrypt(value)
+	} else {
+		out, err = a.Decrypt(strings.TrimPrefix(value, config.EncryptedPrefix))
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, out)
+	return err
+}
+
+// readValue returns the only argument, or stdin without the trailing newline when there is none.
+func readValue(args []string, stdin io.Reader) (string, error) {
+	switch len(args) {
+	case 0:
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("error reading value: %w", err)
+		}
+
+		return strings.TrimRight(string(b), "\r\n"), nil
+	case 1:
+		return args[0], nil
+	default:
+		return "", errors.New("expected a single value")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_keygenToFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "config.key")
+
+	err := run([]string{"keygen", "-o", keyFile}, strings.NewReader(""), &bytes.Buffer{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() == 0 {
+		t.Error("expected the key to be written")
+	}
+
+	err = run([]string{"keygen", "-o", keyFile}, strings.NewReader(""), &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "error creating key file") {
+		t.Errorf("expected an existing key file not to be overwritten, got %v", err)
+	}
+}
+
+func TestRun_encryptDecrypt(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "config.key")
+	if err := run([]string{"keygen", "-o", keyFile}, strings.NewReader(""), &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var encrypted bytes.Buffer
+	err := run([]string{"encrypt", "-key-file", keyFile, "s3cr3t"}, strings.NewReader(""), &encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encrypted.String(), "enc:v1:") {
+		t.Fatalf("expected an enc:v1: value, got %q", encrypted.String())
+	}
+
+	var decrypted bytes.Buffer
+	err = run([]string{"decrypt", "-key-file", keyFile, strings.TrimSpace(encrypted.String())}, strings.NewReader(""), &decrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted.String() != "s3cr3t\n" {
+		t.Errorf("expected %q, got %q", "s3cr3t\n", decrypted.String())
+	}
+}
+
+func TestRun_valueFromStdin(t *testing.T) {
+	var key bytes.Buffer
+	if err := run([]string{"keygen"}, strings.NewReader(""), &key); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TEST_CONFIG_KEY", strings.TrimSpace(key.String()))
+
+	var encrypted bytes.Buffer
+	err := run([]string{"encrypt", "-key-env", "TEST_CONFIG_KEY"}, strings.NewReader("s3cr3t\n"), &encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decrypted bytes.Buffer
+	err = run([]string{"decrypt", "-key-env", "TEST_CONFIG_KEY"}, &encrypted, &decrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted.String() != "s3cr3t\n" {
+		t.Errorf("expected the trailing newline of stdin to be trimmed, got %q", decrypted.String())
+	}
+}
+
+func TestRun_errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "no command", args: nil, want: "usage: configcrypt"},
+		{name: "unknown command", args: []string{"rotate"}, want: `unknown command "rotate"`},
+		{name: "several values", args: []string{"encrypt", "-key-env", "TEST_CONFIG_KEY", "a", "b"}, want: "expected a single value"},
+	}
+
+	var key bytes.Buffer
+	if err := run([]string{"keygen"}, strings.NewReader(""), &key); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_CONFIG_KEY", strings.TrimSpace(key.String()))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := run(tt.args, strings.NewReader(""), &bytes.Buffer{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("run(%q) error = %v, want %q", tt.args, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+// Command configdoc generates a .env.example file or a Markdown reference from a config struct.
+//
+// The struct is read by a small program that imports its package and calls config.Variables,
+// so the names match exactly what config.Load reads. It has to run inside the module of the
+// package, usually through go:generate:
+//
+//	//go:generate go run github.com/pushkar-anand/build-with-go/cmd/configdoc -type Config -prefix APP_ -o .env.example
+//	//go:generate go run github.com/pushkar-anand/build-with-go/cmd/configdoc -type Config -prefix APP_ -format markdown -o CONFIG.md
+//
+// A main package can't be imported, so the struct must be declared in a library package,
+// e.g. internal/config, and not next to func main. Otherwise, call config.WriteEnvExample or
+// config.WriteMarkdown from the program itself, e.g. behind a flag.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	formatEnv      = "env"
+	formatMarkdown = "markdown"
+)
+
+var programTemplate = template.Must(template.New("main").Parse(`package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pushkar-anand/build-with-go/config"
+	target {{ printf "%q" .Package }}
+)
+
+func main() {
+	vars := config.Variables[target.{{ .Type }}]({{ printf "%q" .Prefix }})
+
+	err := config.{{ .Writer }}(os.Stdout, vars)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+type options struct {
+	Package string
+	Type    string
+	Prefix  string
+	Writer  string
+	output  string
+}
+
+func main() {
+	opts, err := parseOptions(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+
+		_, _ = fmt.Fprintln(os.Stderr, "configdoc:", err)
+		os.Exit(2)
+	}
+
+	err = run(opts)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "configdoc:", err)
+		os.Exit(1)
+	}
+}
+
+func parseOptions(args []string) (*options, error) {
+	fs := flag.NewFlagSet("configdoc", flag.ContinueOnError)
+
+	pkg := fs.String("pkg", ".", "import path or directory of the package with the config struct")
+	typ := fs.String("type", "", "name of the config struct (required)")
+	prefix := fs.String("prefix", "", "prefix of the environment variables, e.g. APP_")
+	format := fs.String("format", formatEnv, "output format: env or markdown")
+	output := fs.String("o", "", "output file, defaults to stdout")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if *typ == "" {
+		return nil, errors.New("-type is required")
+	}
+
+	opts := &options{
+		Type:   *typ,
+		Prefix: *prefix,
+		output: *output,
+	}
+
+	switch *format {
+	case formatEnv:
+		opts.Writer = "WriteEnvExample"
+	case formatMarkdown:
+		opts.Writer = "WriteMarkdown"
+	default:
+		return nil, fmt.Errorf("unsupported format %q, use %s or %s", *format, formatEnv, formatMarkdown)
+	}
+
+	opts.Package, err = resolvePackage(*pkg)
+	if err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// resolvePackage returns the import path of pkg, which may be a directory.
+// It fails for a main package, which the generator program can't import.
+func resolvePackage(pkg string) (string, error) {
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}} {{.Name}}", pkg)
+	cmd.Stderr = os.Stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("error resolving package %s: %w", pkg, err)
+	}
+
+	importPath, name, _ := strings.Cut(strings.TrimSpace(string(out)), " ")
+	if name == "main" {
+		return "", fmt.Errorf("package %s is a main package and can't be imported: "+
+			"move the config struct to a library package, or call config.WriteEnvExample from the program", importPath)
+	}
+
+	return importPath, nil
+}
+
+// run writes the generator program to a temporary directory inside the current module and runs it.
+func run(opts *options) error {
+	dir, err := os.MkdirTemp(".", "configdoc-")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	var program bytes.Buffer
+	err = programTemplate.Execute(&program, opts)
+	if err != nil {
+		return fmt.Errorf("error generating program: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "main.go"), program.Bytes(), 0o644)
+	if err != nil {
+		return fmt.Errorf("error writing program: %w", err)
+	}
+
+	var out bytes.Buffer
+
+	cmd := exec.Command("go", "run", "./"+filepath.ToSlash(dir))
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return fmt.Errorf("error running generator for %s.%s: %w", opts.Package, opts.Type, err)
+	}
+
+	if opts.output == "" {
+		_, err = os.Stdout.Write(out.Bytes())
+		return err
+	}
+
+	return os.WriteFile(opts.output, out.Bytes(), 0o644)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseOptions_errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "missing type", args: []string{"-prefix", "APP_"}, want: "-type is required"},
+		{name: "unknown format", args: []string{"-type", "Config", "-format", "yaml"}, want: `unsupported format "yaml"`},
+		{name: "main package", args: []string{"-type", "Config", "-pkg", "."}, want: "is a main package and can't be imported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOptions(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("parseOptions(%q) error = %v, want %q", tt.args, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and runs the generator program")
+	}
+
+	output := filepath.Join(t.TempDir(), ".env.example")
+
+	opts, err := parseOptions([]string{"-type", "Config", "-pkg", "./testdata/sample", "-prefix", "APP_", "-o", output})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = run(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# Read timeout\n# type: time.Duration\nAPP_SERVER_READ_TIMEOUT=5s\n\n# type: string (secret)\nAPP_PASSWORD=\n"
+	if string(b) != want {
+		t.Errorf("output = %q, want %q", b, want)
+	}
+}
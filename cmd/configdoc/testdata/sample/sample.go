@@ -0,0 +1,11 @@
+// Package sample holds the config struct used by the configdoc tests.
+package sample
+
+import "time"
+
+type Config struct {
+	Server struct {
+		ReadTimeout time.Duration `env:"read_timeout" desc:"Read timeout" default:"5s"`
+	} `env:"server"`
+	Password string `env:"password" secret:"true"`
+}
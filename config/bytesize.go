@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a number of bytes read from a human-readable size, e.g. UPLOAD_LIMIT=10MiB.
+//
+// The unit is case-insensitive and may be separated from the number by a space.
+// B, KB, MB, GB, TB and PB are powers of 1000, KiB, MiB, GiB, TiB and PiB powers of 1024.
+// A number without a unit is a number of bytes.
+type ByteSize int64
+
+// Common sizes, e.g. 64 * config.MiB.
+const (
+	B   ByteSize = 1
+	KiB          = 1024 * B
+	MiB          = 1024 * KiB
+	GiB          = 1024 * MiB
+	TiB          = 1024 * GiB
+	PiB          = 1024 * TiB
+)
+
+var byteSizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"pb":  1e15,
+	"kib": float64(KiB),
+	"mib": float64(MiB),
+	"gib": float64(GiB),
+	"tib": float64(TiB),
+	"pib": float64(PiB),
+}
+
+// ParseByteSize parses a size like 512, 1.5GB or 10 MiB.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+
+	end := 0
+	for end < len(s) && (s[end] == '.' || ('0' <= s[end] && s[end] <= '9')) {
+		end++
+	}
+
+	number, unit := s[:end], strings.ToLower(strings.TrimSpace(s[end:]))
+
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q", s)
+	}
+
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid byte size %q: unknown unit %q", s, s[end:])
+	}
+
+	size := n * multiplier
+	// math.MaxInt64 rounds up to 2^63 as a float64, which doesn't fit in an int64.
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size %q: too large", s)
+	}
+
+	return ByteSize(size), nil
+}
+
+// String returns the size in the largest binary unit that represents it exactly, e.g. 10MiB.
+func (b ByteSize) String() string {
+	units := []struct {
+		name string
+		size ByteSize
+	}{
+		{"PiB", PiB}, {"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB},
+	}
+
+	for _, u := range units {
+		if b != 0 && b%u.size == 0 {
+			return strconv.FormatInt(int64(b/u.size), 10) + u.name
+		}
+	}
+
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
+
+// UnmarshalText parses text with ParseByteSize.
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	size, err := ParseByteSize(string(text))
+	if err != nil {
+		return err
+	}
+
+	*b = size
+
+	return nil
+}
+
+// MarshalText returns the size formatted by String.
+func (b ByteSize) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected ByteSize
+	}{
+		{in: "512", expected: 512},
+		{in: "512B", expected: 512},
+		{in: "1KB", expected: 1000},
+		{in: "1.5GB", expected: 1_500_000_000},
+		{in: "10MiB", expected: 10 * MiB},
+		{in: "10 mib", expected: 10 * MiB},
+		{in: "2TiB", expected: 2 * TiB},
+		{in: "8191PiB", expected: 8191 * PiB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			size, err := ParseByteSize(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, size)
+		})
+	}
+
+	_, err := ParseByteSize("MiB")
+	assert.EqualError(t, err, `invalid byte size "MiB"`)
+
+	_, err = ParseByteSize("10XB")
+	assert.EqualError(t, err, `invalid byte size "10XB": unknown unit "XB"`)
+
+	_, err = ParseByteSize("8192PiB")
+	assert.EqualError(t, err, `invalid byte size "8192PiB": too large`)
+}
+
+func TestByteSize_String(t *testing.T) {
+	assert.Equal(t, "0B", ByteSize(0).String())
+	assert.Equal(t, "1000B", ByteSize(1000).String())
+	assert.Equal(t, "10MiB", (10 * MiB).String())
+	assert.Equal(t, "1536KiB", (1536 * KiB).String())
+}
@@ -1,15 +1,16 @@
 package config
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
+	"reflect"
+	"slices"
 	"strings"
 
-	"github.com/knadh/koanf/parsers/dotenv"
-	"github.com/knadh/koanf/providers/env"
-	"github.com/knadh/koanf/providers/file"
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/knadh/koanf/v2"
 )
 
@@ -17,10 +18,6 @@ const (
 	envTagName = "env"
 )
 
-var (
-	k = koanf.New(".") // global koanf instance
-)
-
 var envKeyModifier = func(prefix string) func(string) string {
 	return func(s string) string {
 		return strings.Replace(
@@ -33,37 +30,88 @@ var envKeyModifier = func(prefix string) func(string) string {
 
 const delim = "."
 
-func ReadFromEnv[T any](envFile string, prefix string) (*T, error) {
-	envFileParser := dotenv.ParserEnv(prefix, delim, envKeyModifier(prefix))
-	osEnvProvider := env.Provider(prefix, delim, envKeyModifier(prefix))
+// envVarName returns the name of the environment variable for the koanf key:
+// "server.read_timeout" with prefix "APP_" is APP_SERVER_READ_TIMEOUT.
+func envVarName(prefix, key string) string {
+	return prefix + strings.ToUpper(strings.ReplaceAll(key, delim, "_"))
+}
 
-	err := k.Load(file.Provider(envFile), envFileParser)
-	if err != nil && !fileNotExistsErr(err) {
-		return nil, fmt.Errorf("error loading config from %s: %w", envFile, err)
-	}
+// envKeyFunc returns a function mapping an environment variable to the koanf key of the field of t
+// it sets, it reverses envVarName: APP_SERVER_READ_TIMEOUT is "server.read_timeout".
+//
+// A variable extending the name of a map field sets one of its elements, APP_LABELS_TEAM_NAME
+// is "labels.team_name", the longest name wins. The other variables don't match any field,
+// their underscores become dots like the keys of the other sources.
+func envKeyFunc(prefix string, t reflect.Type) func(string) string {
+	keys := make(map[string]string)
+	var maps []string
 
-	err = k.Load(osEnvProvider, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error loading config from %s: %w", envFile, err)
+	for _, f := range fields(t) {
+		name := envVarName(prefix, f.key)
+		keys[name] = f.key
+
+		if indirectType(f.typ).Kind() == reflect.Map {
+			maps = append(maps, name)
+		}
 	}
 
-	c, err := unmarshalConfig[T](k)
-	if err != nil {
-		return nil, fmt.Errorf("failed: %w", err)
+	// The longest map names are tried first.
+	slices.SortFunc(maps, func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+
+	return func(name string) string {
+		if key, ok := keys[name]; ok {
+			return key
+		}
+
+		for _, m := range maps {
+			if elem, ok := strings.CutPrefix(name, m+"_"); ok && elem != "" {
+				return keys[m] + delim + strings.ToLower(elem)
+			}
+		}
+
+		return envKeyModifier(prefix)(name)
 	}
+}
 
-	return c, nil
+// ReadFromEnv reads the config of type T from the given dotenv file, the OS environment
+// and the command-line flags, see WithFlags. Values from the OS environment take precedence
+// over the ones in the file, and flags over both.
+//
+// Only the flags named after the fields of T are read, the other arguments of the process,
+// like the flags of the program itself, are skipped.
+//
+// Each call loads into its own Loader, so calls with different files or prefixes don't share state.
+func ReadFromEnv[T any](envFile string, prefix string) (*T, error) {
+	l := NewLoader(
+		WithPrefix(prefix),
+		WithEnvFile(envFile),
+		WithOSEnv(),
+		optionFunc(func(l *Loader) {
+			l.sources = append(l.sources, &flagSource{args: os.Args[1:], skipUnknown: true})
+		}),
+	)
+
+	return Load[T](l)
 }
 
-func unmarshalConfig[T any](k *koanf.Koanf) (*T, error) {
+// unmarshalConfig unmarshals k into a new T, converting the strings with decodeHook.
+// The errors name the environment variable, with the given prefix, of every invalid value.
+func unmarshalConfig[T any](k *koanf.Koanf, prefix string) (*T, error) {
 	c := new(T)
 
 	// Unmarshal the whole thing into a struct.
 	err := k.UnmarshalWithConf("", c, koanf.UnmarshalConf{
 		Tag: envTagName,
+		DecoderConfig: &mapstructure.DecoderConfig{
+			DecodeHook:       decodeHook(),
+			WeaklyTypedInput: true,
+			Result:           c,
+		},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling config: %w", err)
+		return nil, fmt.Errorf("error unmarshalling config: %w", decodeErrors(err, k, reflect.TypeFor[T](), prefix))
 	}
 
 	return c, nil
@@ -69,6 +69,33 @@ func TestReadFromEnv_readFromFileOverrideOS(t *testing.T) {
 	assert.Equal(t, 8080, cfg.Server.Port)
 }
 
+func TestReadFromEnv_flagsOverrideOS(t *testing.T) {
+	t.Setenv("SERVER_HOST", "0.0.0.0")
+	t.Setenv("SERVER_PORT", "8080")
+
+	// The flags of the program and of the test binary are skipped.
+	setArgsForTest(t, "-test.v=true", "--verbose", "--server.port", "9090", "serve", "--", "--server.host=ignored")
+
+	cfg, err := ReadFromEnv[Config](".env", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "0.0.0.0", cfg.Server.Host)
+	assert.Equal(t, 9090, cfg.Server.Port)
+}
+
+// setArgsForTest replaces the command-line arguments of the process, read by ReadFromEnv,
+// with args. The test binary has flags of its own.
+func setArgsForTest(t *testing.T, args ...string) {
+	t.Helper()
+
+	original := os.Args
+	os.Args = append([]string{original[0]}, args...)
+
+	t.Cleanup(func() {
+		os.Args = original
+	})
+}
+
 func createEnvFileForTest(t *testing.T, fileName string, data string) error {
 	t.Helper()
 
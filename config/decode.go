@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/knadh/koanf/v2"
+)
+
+// decodeHook converts the strings read from the environment, dotenv files and flags
+// to the types of the fields:
+//
+//   - time.Duration from 5s, 1h30m
+//   - any encoding.TextUnmarshaler, like ByteSize from 10MiB and net.IP from 10.0.0.1
+//   - net.IPNet from a CIDR, 10.0.0.0/8
+//   - url.URL from https://example.com
+//   - slices from comma-separated lists, a,b,c
+//   - maps from comma-separated key=value pairs, k1=v1,k2=v2
+func decodeHook() mapstructure.DecodeHookFunc {
+	return mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.TextUnmarshallerHookFunc(),
+		mapstructure.StringToIPNetHookFunc(),
+		stringToURLHookFunc,
+		stringToSliceHookFunc,
+		stringToMapHookFunc,
+	)
+}
+
+func stringToURLHookFunc(f reflect.Type, t reflect.Type, data any) (any, error) {
+	if f.Kind() != reflect.String || (t != urlType && t != reflect.PointerTo(urlType)) {
+		return data, nil
+	}
+
+	u, err := url.Parse(data.(string))
+	if err != nil {
+		return nil, err
+	}
+
+	if t == urlType {
+		return *u, nil
+	}
+
+	return u, nil
+}
+
+func stringToSliceHookFunc(f reflect.Type, t reflect.Type, data any) (any, error) {
+	// []byte is left to the default conversion of strings.
+	if f.Kind() != reflect.String || t.Kind() != reflect.Slice || t.Elem().Kind() == reflect.Uint8 {
+		return data, nil
+	}
+
+	return splitList(data.(string)), nil
+}
+
+func stringToMapHookFunc(f reflect.Type, t reflect.Type, data any) (any, error) {
+	if f.Kind() != reflect.String || t.Kind() != reflect.Map {
+		return data, nil
+	}
+
+	m := make(map[string]string)
+	for _, item := range splitList(data.(string)) {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid map item %q, expected key=value", item)
+		}
+
+		m[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return m, nil
+}
+
+// decodeErrors finds the fields of t whose values in k can't be decoded after unmarshalling
+// failed with err. Every field is decoded on its own, so all the invalid values are reported,
+// each with the environment variable of the field and the value, unless the field is a secret.
+// When no single field fails, err is returned as it is.
+func decodeErrors(err error, k *koanf.Koanf, t reflect.Type, prefix string) error {
+	var errs []error
+
+	for _, f := range fields(t) {
+		if !k.Exists(f.key) {
+			continue
+		}
+
+		value := k.Get(f.key)
+
+		d, decoderErr := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook:       decodeHook(),
+			WeaklyTypedInput: true,
+			Result:           reflect.New(f.typ).Interface(),
+		})
+		if decoderErr != nil {
+			return err
+		}
+
+		fieldErr := d.Decode(value)
+		if fieldErr == nil {
+			continue
+		}
+
+		var de *mapstructure.DecodeError
+		if errors.As(fieldErr, &de) {
+			fieldErr = de.Unwrap()
+		}
+
+		shown := fmt.Sprint(value)
+		if f.tag.Get(secretTagName) == "true" {
+			shown = redactedValue
+		}
+
+		errs = append(errs, fmt.Errorf("invalid value %q for %s: %w", shown, envVarName(prefix, f.key), fieldErr))
+	}
+
+	if len(errs) == 0 {
+		return err
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	decodeServer struct {
+		ReadTimeout time.Duration `env:"read_timeout"`
+		UploadLimit ByteSize      `env:"limit"`
+		Origins     []string      `env:"origins"`
+		Ports       []int         `env:"ports"`
+	}
+
+	decodeConfig struct {
+		Server   decodeServer      `env:"server"`
+		Labels   map[string]string `env:"labels"`
+		IP       net.IP            `env:"ip"`
+		Network  net.IPNet         `env:"network"`
+		Trusted  *net.IPNet        `env:"trusted"`
+		Endpoint url.URL           `env:"endpoint"`
+		Callback *url.URL          `env:"callback"`
+		Since    time.Time         `env:"since"`
+	}
+)
+
+func TestLoad_decodeTypes(t *testing.T) {
+	t.Setenv("APP_SERVER_READ_TIMEOUT", "5s")
+	t.Setenv("APP_SERVER_LIMIT", "10MiB")
+	t.Setenv("APP_SERVER_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("APP_SERVER_PORTS", "80,443")
+	t.Setenv("APP_LABELS", "team=core, env=prod")
+	t.Setenv("APP_IP", "10.0.0.1")
+	t.Setenv("APP_NETWORK", "10.0.0.0/8")
+	t.Setenv("APP_TRUSTED", "192.168.0.0/16")
+	t.Setenv("APP_ENDPOINT", "https://api.example.com/v1")
+	t.Setenv("APP_CALLBACK", "https://example.com/callback")
+	t.Setenv("APP_SINCE", "2024-01-02T15:04:05Z")
+
+	cfg, err := Load[decodeConfig](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+	require.NoError(t, err)
+
+	assert.Equal(t, 5*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, 10*MiB, cfg.Server.UploadLimit)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.Server.Origins)
+	assert.Equal(t, []int{80, 443}, cfg.Server.Ports)
+	assert.Equal(t, map[string]string{"team": "core", "env": "prod"}, cfg.Labels)
+	assert.Equal(t, "10.0.0.1", cfg.IP.String())
+	assert.Equal(t, "10.0.0.0/8", cfg.Network.String())
+	require.NotNil(t, cfg.Trusted)
+	assert.Equal(t, "192.168.0.0/16", cfg.Trusted.String())
+	assert.Equal(t, "api.example.com", cfg.Endpoint.Host)
+	require.NotNil(t, cfg.Callback)
+	assert.Equal(t, "/callback", cfg.Callback.Path)
+	assert.Equal(t, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), cfg.Since)
+}
+
+func TestLoad_decodeErrors(t *testing.T) {
+	t.Setenv("APP_SERVER_READ_TIMEOUT", "5 seconds")
+	t.Setenv("APP_SERVER_LIMIT", "10XB")
+	t.Setenv("APP_SERVER_PORTS", "80,http")
+	t.Setenv("APP_LABELS", "team")
+
+	_, err := Load[decodeConfig](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+	require.Error(t, err)
+
+	msg := err.Error()
+	assert.Contains(t, msg, `invalid value "5 seconds" for APP_SERVER_READ_TIMEOUT`)
+	assert.Contains(t, msg, `invalid value "10XB" for APP_SERVER_LIMIT: invalid byte size "10XB": unknown unit "XB"`)
+	assert.Contains(t, msg, `invalid value "80,http" for APP_SERVER_PORTS: cannot parse value as 'int'`)
+	assert.Contains(t, msg, `invalid value "team" for APP_LABELS: invalid map item "team", expected key=value`)
+}
+
+func TestLoad_decodeErrorsRedactSecrets(t *testing.T) {
+	type config struct {
+		Port int `env:"port" secret:"true"`
+	}
+
+	t.Setenv("APP_PORT", "s3cr3t")
+
+	_, err := Load[config](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid value "[REDACTED]" for APP_PORT`)
+	assert.NotContains(t, err.Error(), "s3cr3t")
+}
+
+func TestLoad_underscoredKeys(t *testing.T) {
+	type config struct {
+		Server struct {
+			ReadTimeout time.Duration `env:"read_timeout" default:"5s"`
+		} `env:"server"`
+		Labels      map[string]string `env:"labels"`
+		ExtraLabels map[string]string `env:"labels_extra"`
+	}
+
+	t.Setenv("APP_SERVER_READ_TIMEOUT", "7s")
+	t.Setenv("APP_LABELS_TEAM_NAME", "core")
+	t.Setenv("APP_LABELS_EXTRA_OWNER", "ops")
+
+	cfg, err := Load[config](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+	require.NoError(t, err)
+
+	assert.Equal(t, 7*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, map[string]string{"team_name": "core"}, cfg.Labels)
+	assert.Equal(t, map[string]string{"owner": "ops"}, cfg.ExtraLabels)
+}
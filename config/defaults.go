@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+)
+
+const defaultTagName = "default"
+
+// defaultsSource provides the values of the default tags of the config struct.
+//
+// It is always the first source of a Loader, so every other source overrides it.
+type defaultsSource struct{}
+
+func (s *defaultsSource) String() string {
+	return "defaults"
+}
+
+func (s *defaultsSource) load(k *koanf.Koanf, lc *loadContext) error {
+	values := defaultValues(lc.typ)
+	if len(values) == 0 {
+		return nil
+	}
+
+	err := k.Load(confmap.Provider(values, delim), nil)
+	if err != nil {
+		return fmt.Errorf("error loading config from %s: %w", s, err)
+	}
+
+	return nil
+}
+
+// defaultValues returns the default tag values of t keyed by the koanf key of their field.
+//
+// The values are kept as strings and converted while unmarshalling, like the values
+// from the environment. Defaults of slices are split on commas.
+func defaultValues(t reflect.Type) map[string]any {
+	values := make(map[string]any)
+
+	for _, f := range fields(t) {
+		def, ok := f.tag.Lookup(defaultTagName)
+		if !ok {
+			continue
+		}
+
+		if indirectType(f.typ).Kind() == reflect.Slice {
+			values[f.key] = splitList(def)
+			continue
+		}
+
+		values[f.key] = def
+	}
+
+	return values
+}
+
+// splitList splits a comma-separated list, trimming the spaces around the items.
+func splitList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return []string{}
+	}
+
+	items := strings.Split(s, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+
+	return items
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	defaultsServer struct {
+		Host         string        `env:"host" default:"0.0.0.0"`
+		Port         int           `env:"port" default:"8080"`
+		ReadTimeout  time.Duration `env:"timeout" default:"5s"`
+		AllowedHosts []string      `env:"hosts" default:"a.example.com, b.example.com"`
+		Ports        []int         `env:"ports" default:"80,443"`
+		Debug        bool          `env:"debug" default:"true"`
+	}
+
+	defaultsDB struct {
+		Name     string `env:"name" default:"app"`
+		Replicas int    `default:"2"`
+	}
+
+	defaultsConfig struct {
+		Server defaultsServer `env:"server"`
+		DB     *defaultsDB    `env:"db"`
+		NoTag  string
+	}
+)
+
+func TestLoad_defaults(t *testing.T) {
+	cfg, err := Load[defaultsConfig](NewLoader())
+	require.NoError(t, err)
+
+	expected := defaultsConfig{
+		Server: defaultsServer{
+			Host:         "0.0.0.0",
+			Port:         8080,
+			ReadTimeout:  5 * time.Second,
+			AllowedHosts: []string{"a.example.com", "b.example.com"},
+			Ports:        []int{80, 443},
+			Debug:        true,
+		},
+		DB: &defaultsDB{
+			Name:     "app",
+			Replicas: 2,
+		},
+	}
+
+	assert.Equal(t, expected, *cfg)
+}
+
+func TestLoad_defaultsAreOverridden(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "config.yaml")
+	err := createEnvFileForTest(t, fileName, "server:\n  port: 9000\n  debug: false\n")
+	require.NoError(t, err)
+
+	t.Setenv("APP_SERVER_TIMEOUT", "1m")
+	t.Setenv("APP_DB_REPLICAS", "5")
+
+	l := NewLoader(
+		WithPrefix("APP_"),
+		WithFiles(fileName),
+		WithOSEnv(),
+	)
+
+	cfg, err := Load[defaultsConfig](l)
+	require.NoError(t, err)
+
+	assert.Equal(t, "0.0.0.0", cfg.Server.Host)
+	assert.Equal(t, 9000, cfg.Server.Port)
+	assert.False(t, cfg.Server.Debug)
+	assert.Equal(t, time.Minute, cfg.Server.ReadTimeout)
+	assert.Equal(t, "app", cfg.DB.Name)
+	assert.Equal(t, 5, cfg.DB.Replicas)
+}
+
+func TestLoad_invalidDefault(t *testing.T) {
+	type invalid struct {
+		Port int `env:"port" default:"not-a-number"`
+	}
+
+	_, err := Load[invalid](NewLoader())
+	assert.Error(t, err)
+}
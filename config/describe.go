@@ -0,0 +1,119 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	secretTagName = "secret"
+	redactedValue = "[REDACTED]"
+)
+
+// Setting describes the effective value of a single config field.
+type Setting struct {
+	// Key is the key of the field in config files, e.g. "server.port".
+	Key string
+	// EnvVar is the full name of the environment variable of the field, e.g. APP_SERVER_PORT.
+	EnvVar string
+	// Value is the final value of the field, redacted for secrets that are set.
+	Value any
+	// Source is the name of the last source that set the field,
+	// "defaults" for default tags and empty when no source set it.
+	Source string
+	// Secret is set for the fields tagged with `secret:"true"`.
+	Secret bool
+}
+
+// Describe loads the config like Load and reports every field of T with its value
+// and the source that provided it, in the order of the struct fields.
+//
+// The fields tagged with `secret:"true"` are redacted. The config is not validated,
+// so Describe can be used to debug a config that Load rejects.
+func Describe[T any](l *Loader) ([]Setting, error) {
+	c, origins, err := loadConfig[T](l)
+	if err != nil {
+		return nil, err
+	}
+
+	return describe(l.prefix, c, origins), nil
+}
+
+// Dump writes the settings to w as a table.
+func Dump(w io.Writer, settings []Setting) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(tw, "KEY\tENV\tVALUE\tSOURCE")
+
+	for _, s := range settings {
+		source := s.Source
+		if source == "" {
+			source = "-"
+		}
+
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%v\t%s\n", s.Key, s.EnvVar, s.Value, source)
+	}
+
+	return tw.Flush()
+}
+
+func describe(prefix string, c any, origins map[string]string) []Setting {
+	v := reflect.ValueOf(c)
+
+	var settings []Setting
+	for _, f := range fields(v.Type()) {
+		s := Setting{
+			Key:    f.key,
+			EnvVar: envVarName(prefix, f.key),
+			Value:  fieldValue(v, f).Interface(),
+			Source: origins[f.key],
+			Secret: f.tag.Get(secretTagName) == "true",
+		}
+
+		if s.Secret && !fieldValue(v, f).IsZero() {
+			s.Value = redactedValue
+		}
+
+		settings = append(settings, s)
+	}
+
+	return settings
+}
+
+// fieldValue returns the value of the field f in the struct v,
+// or the zero value of the field when one of the structs on the path is a nil pointer.
+func fieldValue(v reflect.Value, f field) reflect.Value {
+	for _, name := range strings.Split(f.path, delim) {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Zero(f.typ)
+			}
+
+			v = v.Elem()
+		}
+
+		v = v.FieldByName(name)
+	}
+
+	return v
+}
+
+// logSettings logs every setting at info level.
+func logSettings(log *slog.Logger, settings []Setting) {
+	for _, s := range settings {
+		log.LogAttrs(
+			context.Background(),
+			slog.LevelInfo,
+			"config setting",
+			slog.String("key", s.Key),
+			slog.String("env", s.EnvVar),
+			slog.Any("value", s.Value),
+			slog.String("source", s.Source),
+		)
+	}
+}
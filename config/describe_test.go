@@ -0,0 +1,84 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	describedDB struct {
+		Password string `env:"password" secret:"true"`
+		Token    string `env:"token" secret:"true"`
+	}
+
+	describedConfig struct {
+		Server Server       `env:"server"`
+		Name   string       `env:"name" default:"app"`
+		DB     *describedDB `env:"db"`
+	}
+)
+
+func TestDescribe(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), ".env")
+	err := createEnvFileForTest(t, fileName, "APP_SERVER_HOST=localhost\nAPP_SERVER_PORT=1000\n")
+	require.NoError(t, err)
+
+	t.Setenv("APP_SERVER_PORT", "2000")
+	t.Setenv("APP_DB_PASSWORD", "s3cr3t")
+
+	l := NewLoader(WithPrefix("APP_"), WithEnvFile(fileName), WithOSEnv())
+
+	settings, err := Describe[describedConfig](l)
+	require.NoError(t, err)
+
+	expected := []Setting{
+		{Key: "server.host", EnvVar: "APP_SERVER_HOST", Value: "localhost", Source: fileName},
+		{Key: "server.port", EnvVar: "APP_SERVER_PORT", Value: 2000, Source: "environment"},
+		{Key: "name", EnvVar: "APP_NAME", Value: "app", Source: "defaults"},
+		{Key: "db.password", EnvVar: "APP_DB_PASSWORD", Value: redactedValue, Source: "environment", Secret: true},
+		{Key: "db.token", EnvVar: "APP_DB_TOKEN", Value: "", Source: "", Secret: true},
+	}
+	assert.Equal(t, expected, settings)
+
+	var buf bytes.Buffer
+	require.NoError(t, Dump(&buf, settings))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 6)
+	assert.Equal(t, []string{"KEY", "ENV", "VALUE", "SOURCE"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"server.port", "APP_SERVER_PORT", "2000", "environment"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"db.token", "APP_DB_TOKEN", "-"}, strings.Fields(lines[5]))
+	assert.NotContains(t, buf.String(), "s3cr3t")
+}
+
+func TestLoad_withDumpLogger(t *testing.T) {
+	t.Setenv("APP_DB_PASSWORD", "s3cr3t")
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	_, err := Load[describedConfig](NewLoader(WithPrefix("APP_"), WithOSEnv(), WithDumpLogger(log)))
+	require.NoError(t, err)
+
+	assert.NotContains(t, buf.String(), "s3cr3t")
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+
+	require.Len(t, records, 5)
+	assert.Equal(t, "config setting", records[3]["msg"])
+	assert.Equal(t, "db.password", records[3]["key"])
+	assert.Equal(t, redactedValue, records[3]["value"])
+	assert.Equal(t, "environment", records[3]["source"])
+}
@@ -0,0 +1,318 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type (
+	// dotenvEntry is a single assignment of a dotenv file.
+	dotenvEntry struct {
+		name string
+		// raw is the value as written in the file, without the quotes.
+		raw  string
+		line int
+		// quote is the quote around the value, 0 when it is not quoted.
+		quote byte
+	}
+
+	// dotenvResolver expands the variable references in the values of a dotenv file.
+	dotenvResolver struct {
+		entries []dotenvEntry
+		// values holds the expanded values of the entries resolved so far.
+		values []string
+	}
+)
+
+// parseDotenv parses a dotenv file and expands the variable references in its values.
+//
+// Values can be unquoted, single-quoted or double-quoted, quoted values can span several lines.
+// In unquoted and double-quoted values, ${VAR} and $VAR are replaced by the value of VAR,
+// ${VAR:-default} falls back to default when VAR is unset or empty, ${VAR-default} when it is unset.
+// A variable resolves to its latest assignment above the reference, then to the OS environment,
+// like in a shell: the assignments below the reference are not used. \$ escapes a dollar sign,
+// single-quoted values are taken literally.
+func parseDotenv(data string) (map[string]string, error) {
+	entries, err := scanDotenv(data)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &dotenvResolver{
+		entries: entries,
+		values:  make([]string, 0, len(entries)),
+	}
+
+	vars := make(map[string]string, len(entries))
+	for i, e := range entries {
+		value, err := r.expand(i, e.raw)
+		if err != nil {
+			return nil, err
+		}
+
+		r.values = append(r.values, value)
+		vars[e.name] = value
+	}
+
+	return vars, nil
+}
+
+// scanDotenv splits a dotenv file into its assignments.
+func scanDotenv(data string) ([]dotenvEntry, error) {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+
+	var (
+		entries []dotenvEntry
+		line    = 1
+	)
+
+	for len(data) > 0 {
+		var current string
+		current, data, _ = strings.Cut(data, "\n")
+		start := line
+		line++
+
+		current = strings.TrimSpace(current)
+		if current == "" || strings.HasPrefix(current, "#") {
+			continue
+		}
+
+		current = strings.TrimPrefix(current, "export ")
+
+		name, value, found := strings.Cut(current, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected NAME=value", start)
+		}
+
+		name = strings.TrimSpace(name)
+		if !isValidEnvName(name) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", start, name)
+		}
+
+		e := dotenvEntry{name: name, line: start}
+		value = strings.TrimLeft(value, " \t")
+
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			e.raw = stripInlineComment(value)
+			entries = append(entries, e)
+			continue
+		}
+
+		e.quote = value[0]
+
+		// Quoted values may continue on the next lines.
+		value = value[1:]
+		end := closingQuote(value, e.quote)
+		for end < 0 && len(data) > 0 {
+			var next string
+			next, data, _ = strings.Cut(data, "\n")
+			line++
+
+			value += "\n" + next
+			end = closingQuote(value, e.quote)
+		}
+
+		if end < 0 {
+			return nil, fmt.Errorf("line %d: unterminated quoted value of %s", start, name)
+		}
+
+		rest := strings.TrimSpace(value[end+1:])
+		if rest != "" && !strings.HasPrefix(rest, "#") {
+			return nil, fmt.Errorf("line %d: unexpected %q after the quoted value of %s", start, rest, name)
+		}
+
+		e.raw = value[:end]
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+// closingQuote returns the index of the quote closing s, skipping the escaped double quotes.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+
+	return -1
+}
+
+// stripInlineComment removes a comment starting with " #" from an unquoted value.
+func stripInlineComment(s string) string {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '#' && (s[i-1] == ' ' || s[i-1] == '\t') {
+			s = s[:i]
+			break
+		}
+	}
+
+	return strings.TrimSpace(s)
+}
+
+// expand replaces the references and the escapes in s, a part of the value of the entry i.
+func (r *dotenvResolver) expand(i int, s string) (string, error) {
+	e := r.entries[i]
+	if e.quote == '\'' {
+		return s, nil
+	}
+
+	var b strings.Builder
+
+	for pos := 0; pos < len(s); {
+		c := s[pos]
+
+		switch {
+		case c == '\\' && pos+1 < len(s):
+			b.WriteString(unescape(s[pos+1], e.quote))
+			pos += 2
+
+		case c == '$' && pos+1 < len(s) && s[pos+1] == '{':
+			end := closingBrace(s, pos+2)
+			if end < 0 {
+				return "", fmt.Errorf("line %d: unterminated ${ in the value of %s", e.line, e.name)
+			}
+
+			value, err := r.expandReference(i, s[pos+2:end])
+			if err != nil {
+				return "", err
+			}
+
+			b.WriteString(value)
+			pos = end + 1
+
+		case c == '$' && pos+1 < len(s) && isEnvNameStart(s[pos+1]):
+			end := pos + 1
+			for end < len(s) && isEnvNameChar(s[end]) {
+				end++
+			}
+
+			value, _ := r.lookup(i, s[pos+1:end])
+			b.WriteString(value)
+			pos = end
+
+		default:
+			b.WriteByte(c)
+			pos++
+		}
+	}
+
+	return b.String(), nil
+}
+
+// expandReference expands the content of ${...}: a name, optionally followed by :-default or -default.
+func (r *dotenvResolver) expandReference(i int, ref string) (string, error) {
+	e := r.entries[i]
+
+	end := 0
+	for end < len(ref) && isEnvNameChar(ref[end]) {
+		end++
+	}
+
+	name, op := ref[:end], ref[end:]
+	if name == "" || !isEnvNameStart(name[0]) {
+		return "", fmt.Errorf("line %d: invalid reference ${%s} in the value of %s", e.line, ref, e.name)
+	}
+
+	value, set := r.lookup(i, name)
+
+	switch {
+	case op == "":
+		return value, nil
+	case strings.HasPrefix(op, ":-"):
+		if set && value != "" {
+			return value, nil
+		}
+
+		return r.expand(i, op[2:])
+	case strings.HasPrefix(op, "-"):
+		if set {
+			return value, nil
+		}
+
+		return r.expand(i, op[1:])
+	default:
+		return "", fmt.Errorf("line %d: invalid reference ${%s} in the value of %s", e.line, ref, e.name)
+	}
+}
+
+// lookup returns the value of the variable name referenced by the entry i:
+// its latest assignment above the entry, or its value in the OS environment.
+func (r *dotenvResolver) lookup(i int, name string) (string, bool) {
+	for j := i - 1; j >= 0; j-- {
+		if r.entries[j].name == name {
+			return r.values[j], true
+		}
+	}
+
+	return os.LookupEnv(name)
+}
+
+// unescape returns the character escaped by a backslash.
+// Double-quoted values support \n, \r, \t, \", \\ and \$, unquoted values only \\ and \$.
+func unescape(c byte, quote byte) string {
+	switch {
+	case c == '$' || c == '\\':
+		return string(c)
+	case quote != '"':
+		return "\\" + string(c)
+	case c == 'n':
+		return "\n"
+	case c == 'r':
+		return "\r"
+	case c == 't':
+		return "\t"
+	case c == '"':
+		return `"`
+	default:
+		return "\\" + string(c)
+	}
+}
+
+// closingBrace returns the index of the brace closing a ${ starting before from, or -1.
+func closingBrace(s string, from int) int {
+	depth := 1
+	for i := from; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func isValidEnvName(name string) bool {
+	if name == "" || !isEnvNameStart(name[0]) {
+		return false
+	}
+
+	for i := range len(name) {
+		if !isEnvNameChar(name[i]) && name[i] != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isEnvNameStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isEnvNameChar(c byte) bool {
+	return isEnvNameStart(c) || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDotenv(t *testing.T) {
+	t.Setenv("DOTENV_TEST_HOME", "/home/app")
+	t.Setenv("DOTENV_TEST_EMPTY", "")
+
+	tests := []struct {
+		name     string
+		data     string
+		expected map[string]string
+	}{
+		{
+			name:     "earlier key",
+			data:     "HOST=localhost\nPORT=8080\nURL=http://${HOST}:$PORT\n",
+			expected: map[string]string{"HOST": "localhost", "PORT": "8080", "URL": "http://localhost:8080"},
+		},
+		{
+			name: "later keys are not used",
+			data: "URL=http://${DOTENV_TEST_HOST:-default}:$DOTENV_TEST_PORT\nDOTENV_TEST_HOST=localhost\n" +
+				"DOTENV_TEST_PORT=8080\nDATA=$DOTENV_TEST_HOME\nDOTENV_TEST_HOME=/tmp\n",
+			expected: map[string]string{
+				"URL": "http://default:", "DOTENV_TEST_HOST": "localhost", "DOTENV_TEST_PORT": "8080",
+				"DATA": "/home/app", "DOTENV_TEST_HOME": "/tmp",
+			},
+		},
+		{
+			name:     "self reference",
+			data:     "DIRS=${DOTENV_TEST_HOME}\nDIRS=$DIRS:/bin\nSELF=${DOTENV_TEST_SELF}\nDOTENV_TEST_SELF=x\n",
+			expected: map[string]string{"DIRS": "/home/app:/bin", "SELF": "", "DOTENV_TEST_SELF": "x"},
+		},
+		{
+			name:     "os environment",
+			data:     "DATA=${DOTENV_TEST_HOME}/data\n",
+			expected: map[string]string{"DATA": "/home/app/data"},
+		},
+		{
+			name:     "latest assignment above the reference",
+			data:     "LEVEL=info\nFIRST=$LEVEL\nLEVEL=debug\nSECOND=$LEVEL\n",
+			expected: map[string]string{"LEVEL": "debug", "FIRST": "info", "SECOND": "debug"},
+		},
+		{
+			name: "defaults",
+			data: "A=${DOTENV_TEST_UNSET:-fallback}\nB=${DOTENV_TEST_EMPTY:-fallback}\n" +
+				"C=${DOTENV_TEST_EMPTY-fallback}\nD=${DOTENV_TEST_UNSET:-${DOTENV_TEST_HOME}}\n",
+			expected: map[string]string{"A": "fallback", "B": "fallback", "C": "", "D": "/home/app"},
+		},
+		{
+			name:     "escapes",
+			data:     "PRICE=\\$5\nQUOTED=\"a\\tb \\$HOME \\\"c\\\"\"\n",
+			expected: map[string]string{"PRICE": "$5", "QUOTED": "a\tb $HOME \"c\""},
+		},
+		{
+			name:     "single quotes are literal",
+			data:     "HOST=localhost\nRAW='${HOST} \\n'\n",
+			expected: map[string]string{"HOST": "localhost", "RAW": "${HOST} \\n"},
+		},
+		{
+			name:     "multiline double quotes",
+			data:     "NAME=app\nKEY=\"first $NAME\nsecond\"\nNEXT=1\n",
+			expected: map[string]string{"NAME": "app", "KEY": "first app\nsecond", "NEXT": "1"},
+		},
+		{
+			name:     "comments and export",
+			data:     "# comment\n\nexport HOST=localhost # inline\nCOLOR=#fff\nQUOTED=\"a # b\" # comment\n",
+			expected: map[string]string{"HOST": "localhost", "COLOR": "#fff", "QUOTED": "a # b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vars, err := parseDotenv(tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, vars)
+		})
+	}
+}
+
+func TestParseDotenv_errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{
+			name: "unterminated reference",
+			data: "A=1\nB=${A\n",
+			err:  "line 2: unterminated ${ in the value of B",
+		},
+		{
+			name: "invalid reference",
+			data: "A=${1A}\n",
+			err:  "line 1: invalid reference ${1A} in the value of A",
+		},
+		{
+			name: "unterminated quote",
+			data: "A=1\nB=\"open\nC=2\n",
+			err:  "line 2: unterminated quoted value of B",
+		},
+		{
+			name: "missing assignment",
+			data: "A=1\nB\n",
+			err:  "line 2: expected NAME=value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseDotenv(tt.data)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestLoad_envFileInterpolation(t *testing.T) {
+	t.Setenv("SERVER_HOST", "")
+	t.Setenv("DOTENV_TEST_PORT", "9090")
+
+	path := filepath.Join(t.TempDir(), ".env")
+	err := createEnvFileForTest(t, path, "HOST=example.com\nSERVER_HOST=${HOST}\nSERVER_PORT=${DOTENV_TEST_PORT:-8080}\n")
+	require.NoError(t, err)
+
+	cfg, err := Load[Config](NewLoader(WithEnvFile(path)))
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com", cfg.Server.Host)
+	assert.Equal(t, 9090, cfg.Server.Port)
+}
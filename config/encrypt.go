@@ -0,0 +1,165 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// EncryptedPrefix marks an encrypted config value, e.g. DB_PASSWORD=enc:v1:<ciphertext>.
+const EncryptedPrefix = "enc:v1:"
+
+// aesKeySize is the size of an AES-256 key in bytes.
+const aesKeySize = 32
+
+type (
+	// Decrypter decrypts the config values starting with EncryptedPrefix, see WithDecrypter.
+	Decrypter interface {
+		// Decrypt returns the plaintext of ciphertext, the value without EncryptedPrefix.
+		Decrypt(ciphertext string) (string, error)
+	}
+
+	// AESGCM encrypts and decrypts config values with AES-256-GCM.
+	//
+	// The ciphertext is the base64 encoding of a random nonce followed by the sealed value.
+	AESGCM struct {
+		aead cipher.AEAD
+	}
+)
+
+var errNoDecrypter = errors.New("value is encrypted but no decrypter is configured")
+
+// NewAESGCM creates an AESGCM from a 32 bytes key.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	if len(key) != aesKeySize {
+		return nil, fmt.Errorf("invalid key size %d, AES-256 needs %d bytes", len(key), aesKeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cipher: %w", err)
+	}
+
+	return &AESGCM{aead: aead}, nil
+}
+
+// NewAESGCMFromFile creates an AESGCM with the base64 encoded key stored in the file at path.
+func NewAESGCMFromFile(path string) (*AESGCM, error) {
+	encoded, err := readSecretFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading key file: %w", err)
+	}
+
+	return newAESGCMFromBase64(encoded)
+}
+
+// NewAESGCMFromEnv creates an AESGCM with the base64 encoded key stored in the environment variable name.
+func NewAESGCMFromEnv(name string) (*AESGCM, error) {
+	encoded, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("key variable %s is not set", name)
+	}
+
+	return newAESGCMFromBase64(encoded)
+}
+
+// GenerateAESGCMKey returns a new random key, base64 encoded, for NewAESGCMFromFile and NewAESGCMFromEnv.
+func GenerateAESGCMKey() (string, error) {
+	key := make([]byte, aesKeySize)
+
+	_, err := rand.Read(key)
+	if err != nil {
+		return "", fmt.Errorf("error generating key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func newAESGCMFromBase64(encoded string) (*AESGCM, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding key: %w", err)
+	}
+
+	return NewAESGCM(key)
+}
+
+// Encrypt encrypts plaintext and returns it as a config value, with EncryptedPrefix.
+func (a *AESGCM) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", fmt.Errorf("error generating nonce: %w", err)
+	}
+
+	sealed := a.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a ciphertext returned by Encrypt, without EncryptedPrefix.
+func (a *AESGCM) Decrypt(ciphertext string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("error decoding ciphertext: %w", err)
+	}
+
+	if len(sealed) < a.aead.NonceSize() {
+		return "", errors.New("ciphertext is too short")
+	}
+
+	nonce, sealed := sealed[:a.aead.NonceSize()], sealed[a.aead.NonceSize():]
+
+	plaintext, err := a.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		// The error of Open never tells more than a wrong key or a tampered value.
+		return "", errors.New("error decrypting value: wrong key or corrupted ciphertext")
+	}
+
+	return string(plaintext), nil
+}
+
+// decryptValues replaces the string values of k starting with EncryptedPrefix with their plaintext.
+// Only the keys of the known fields are decrypted. The errors name the key but never the value.
+func decryptValues(k *koanf.Koanf, d Decrypter, known []field) error {
+	for key, value := range k.All() {
+		s, ok := value.(string)
+		if !ok || !knownKey(known, key) {
+			continue
+		}
+
+		ciphertext, isEncrypted := strings.CutPrefix(s, EncryptedPrefix)
+		if !isEncrypted {
+			continue
+		}
+
+		if d == nil {
+			return fmt.Errorf("error decrypting %s: %w", key, errNoDecrypter)
+		}
+
+		plaintext, err := d.Decrypt(ciphertext)
+		if err != nil {
+			return fmt.Errorf("error decrypting %s: %w", key, err)
+		}
+
+		err = k.Set(key, plaintext)
+		if err != nil {
+			return fmt.Errorf("error setting %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
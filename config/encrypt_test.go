@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAESGCM(t *testing.T) {
+	key, err := GenerateAESGCMKey()
+	require.NoError(t, err)
+
+	t.Setenv("CONFIG_KEY", key)
+
+	a, err := NewAESGCMFromEnv("CONFIG_KEY")
+	require.NoError(t, err)
+
+	value, err := a.Encrypt("s3cr3t")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(value, EncryptedPrefix))
+	assert.NotContains(t, value, "s3cr3t")
+
+	plaintext, err := a.Decrypt(strings.TrimPrefix(value, EncryptedPrefix))
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", plaintext)
+
+	t.Run("wrong key", func(t *testing.T) {
+		otherKey, err := GenerateAESGCMKey()
+		require.NoError(t, err)
+
+		keyFile := filepath.Join(t.TempDir(), "config.key")
+		err = createEnvFileForTest(t, keyFile, otherKey+"\n")
+		require.NoError(t, err)
+
+		other, err := NewAESGCMFromFile(keyFile)
+		require.NoError(t, err)
+
+		_, err = other.Decrypt(strings.TrimPrefix(value, EncryptedPrefix))
+		assert.EqualError(t, err, "error decrypting value: wrong key or corrupted ciphertext")
+	})
+
+	t.Run("invalid key size", func(t *testing.T) {
+		_, err := NewAESGCM([]byte("short"))
+		assert.EqualError(t, err, "invalid key size 5, AES-256 needs 32 bytes")
+	})
+}
+
+func TestLoad_withDecrypter(t *testing.T) {
+	key, err := GenerateAESGCMKey()
+	require.NoError(t, err)
+
+	t.Setenv("CONFIG_KEY", key)
+
+	a, err := NewAESGCMFromEnv("CONFIG_KEY")
+	require.NoError(t, err)
+
+	password, err := a.Encrypt("s3cr3t")
+	require.NoError(t, err)
+
+	fileName := filepath.Join(t.TempDir(), ".env")
+	err = createEnvFileForTest(t, fileName, fmt.Sprintf("APP_DB_USER=admin\nAPP_DB_PASSWORD=%s\n", password))
+	require.NoError(t, err)
+
+	t.Run("encrypted values are decrypted", func(t *testing.T) {
+		cfg, err := Load[secretsConfig](NewLoader(WithPrefix("APP_"), WithEnvFile(fileName), WithDecrypter(a)))
+		require.NoError(t, err)
+
+		assert.Equal(t, "admin", cfg.DB.User)
+		assert.Equal(t, "s3cr3t", cfg.DB.Password)
+	})
+
+	t.Run("encrypted value in a secret file", func(t *testing.T) {
+		secret := filepath.Join(t.TempDir(), "db_password")
+		err := createEnvFileForTest(t, secret, password+"\n")
+		require.NoError(t, err)
+
+		t.Setenv("APP_DB_PASSWORD_FILE", secret)
+
+		cfg, err := Load[secretsConfig](NewLoader(WithPrefix("APP_"), WithOSEnv(), WithDecrypter(a)))
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", cfg.DB.Password)
+	})
+
+	t.Run("without decrypter", func(t *testing.T) {
+		_, err := Load[secretsConfig](NewLoader(WithPrefix("APP_"), WithEnvFile(fileName)))
+		require.ErrorIs(t, err, errNoDecrypter)
+		assert.NotContains(t, err.Error(), password)
+	})
+
+	t.Run("unrelated encrypted values are ignored", func(t *testing.T) {
+		t.Setenv("FOO_BAR", "enc:v1:abc")
+		t.Setenv("SERVER_HOST", "localhost")
+
+		cfg, err := Load[Config](NewLoader(WithOSEnv()))
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", cfg.Server.Host)
+	})
+}
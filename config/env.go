@@ -0,0 +1,95 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+)
+
+type (
+	envFileSource struct {
+		path string
+	}
+
+	osEnvSource struct{}
+)
+
+func (s *envFileSource) String() string {
+	return s.path
+}
+
+// load reads the dotenv file and expands the variable references in its values.
+// A missing file is not an error.
+func (s *envFileSource) load(k *koanf.Koanf, lc *loadContext) error {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if fileNotExistsErr(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error loading config from %s: %w", s, err)
+	}
+
+	vars, err := parseDotenv(string(b))
+	if err != nil {
+		return fmt.Errorf("error loading config from %s: %w", s, err)
+	}
+
+	err = loadEnvVars(k, vars, lc)
+	if err != nil {
+		return fmt.Errorf("error loading config from %s: %w", s, err)
+	}
+
+	return nil
+}
+
+func (s *envFileSource) watch(ctx context.Context, changed func(), failed func(error)) error {
+	return watchFile(ctx, s.path, changed, failed)
+}
+
+func (s *osEnvSource) String() string {
+	return "environment"
+}
+
+func (s *osEnvSource) load(k *koanf.Koanf, lc *loadContext) error {
+	vars := make(map[string]string)
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		vars[name] = value
+	}
+
+	err := loadEnvVars(k, vars, lc)
+	if err != nil {
+		return fmt.Errorf("error loading config from %s: %w", s, err)
+	}
+
+	return nil
+}
+
+// loadEnvVars loads the variables starting with the prefix into k.
+//
+// A variable named like a config variable with a _FILE suffix, e.g. APP_DB_PASSWORD_FILE,
+// is replaced by the content of the file it points to.
+func loadEnvVars(k *koanf.Koanf, vars map[string]string, lc *loadContext) error {
+	vars, err := resolveSecretFiles(vars, lc)
+	if err != nil {
+		return err
+	}
+
+	toKey := envKeyFunc(lc.prefix, lc.typ)
+
+	values := make(map[string]any)
+	for name, value := range vars {
+		if !strings.HasPrefix(name, lc.prefix) {
+			continue
+		}
+
+		values[toKey(name)] = value
+	}
+
+	return k.Load(confmap.Provider(values, delim), nil)
+}
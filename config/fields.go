@@ -0,0 +1,113 @@
+package config
+
+import (
+	"encoding"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// field is a leaf value of a config struct, reachable from the root through env tags.
+type field struct {
+	// key is the koanf key of the field, e.g. "server.port".
+	key string
+	// path is the Go field path of the field, e.g. "Server.Port".
+	path string
+	typ  reflect.Type
+	tag  reflect.StructTag
+}
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+	// urlType and ipNetType are structs decoded from a single string, see decodeHook.
+	urlType   = reflect.TypeFor[url.URL]()
+	ipNetType = reflect.TypeFor[net.IPNet]()
+)
+
+// fields returns the leaf fields of the struct type t in declaration order.
+//
+// Fields are mapped to keys the same way they are unmarshalled: by their env tag,
+// or by their lowercased name when the tag is missing. Nested structs add a level to the key,
+// unless they are embedded with the squash option.
+func fields(t reflect.Type) []field {
+	var out []field
+	walkFields(t, "", "", func(f field) {
+		out = append(out, f)
+	})
+
+	return out
+}
+
+func walkFields(t reflect.Type, keyPrefix, pathPrefix string, fn func(field)) {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(sf.Tag.Get(envTagName), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Keys from the environment are always lowercase, unmarshalling matches names case-insensitively.
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+
+		key := joinKey(keyPrefix, name)
+		path := joinKey(pathPrefix, sf.Name)
+
+		if !isLeafType(sf.Type) {
+			if sf.Anonymous && strings.Contains(opts, "squash") {
+				key = keyPrefix
+			}
+
+			walkFields(sf.Type, key, path, fn)
+			continue
+		}
+
+		fn(field{
+			key:  key,
+			path: path,
+			typ:  sf.Type,
+			tag:  sf.Tag,
+		})
+	}
+}
+
+// isLeafType reports whether t holds a single value instead of a group of fields.
+func isLeafType(t reflect.Type) bool {
+	if reflect.PointerTo(indirectType(t)).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	if indirectType(t) == urlType || indirectType(t) == ipNetType {
+		return true
+	}
+
+	return indirectType(t).Kind() != reflect.Struct
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func joinKey(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + delim + name
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/parsers/toml/v2"
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/providers/file"
+	"github.com/knadh/koanf/v2"
+)
+
+// fileSource is a structured config file, parsed according to its extension.
+//
+// Keys in the file are matched against the env tags of the config struct,
+// so `server: {port: 8080}` in YAML sets the same field as SERVER_PORT.
+type fileSource struct {
+	path     string
+	optional bool
+}
+
+func (s *fileSource) String() string {
+	return s.path
+}
+
+func (s *fileSource) load(k *koanf.Koanf, _ *loadContext) error {
+	parser, err := parserForFile(s.path)
+	if err != nil {
+		return err
+	}
+
+	err = k.Load(file.Provider(s.path), parser)
+	if err != nil {
+		if s.optional && fileNotExistsErr(err) {
+			return nil
+		}
+
+		return fmt.Errorf("error loading config from %s: %w", s, err)
+	}
+
+	return nil
+}
+
+func (s *fileSource) watch(ctx context.Context, changed func(), failed func(error)) error {
+	return watchFile(ctx, s.path, changed, failed)
+}
+
+// parserForFile returns the koanf parser for the extension of path.
+func parserForFile(path string) (koanf.Parser, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return yaml.Parser(), nil
+	case ".json":
+		return json.Parser(), nil
+	case ".toml":
+		return toml.Parser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q for %s", ext, path)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader_layeredFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	base := filepath.Join(dir, "config.yaml")
+	err := createEnvFileForTest(t, base, "server:\n  host: base\n  port: 1000\n")
+	require.NoError(t, err)
+
+	override := filepath.Join(dir, "config.production.json")
+	err = createEnvFileForTest(t, override, `{"server": {"port": 2000}}`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		env      map[string]string
+		expected Config
+	}{
+		{
+			name:     "single yaml file",
+			opts:     []Option{WithFiles(base)},
+			expected: Config{Server: Server{Host: "base", Port: 1000}},
+		},
+		{
+			name:     "later file overrides earlier file",
+			opts:     []Option{WithFiles(base, override)},
+			expected: Config{Server: Server{Host: "base", Port: 2000}},
+		},
+		{
+			name:     "environment overrides files",
+			opts:     []Option{WithFiles(base, override), WithOSEnv()},
+			env:      map[string]string{"APP_SERVER_HOST": "env"},
+			expected: Config{Server: Server{Host: "env", Port: 2000}},
+		},
+		{
+			name:     "missing optional file is ignored",
+			opts:     []Option{WithFiles(base), WithOptionalFiles(filepath.Join(dir, "missing.toml"))},
+			expected: Config{Server: Server{Host: "base", Port: 1000}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			opts := append([]Option{WithPrefix("APP_")}, tt.opts...)
+
+			cfg, err := Load[Config](NewLoader(opts...))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *cfg)
+		})
+	}
+}
+
+func TestLoader_tomlFile(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "config.toml")
+	err := createEnvFileForTest(t, fileName, "[server]\nhost = \"toml\"\nport = 3000\n")
+	require.NoError(t, err)
+
+	cfg, err := Load[Config](NewLoader(WithFiles(fileName)))
+	require.NoError(t, err)
+
+	assert.Equal(t, Config{Server: Server{Host: "toml", Port: 3000}}, *cfg)
+}
+
+func TestLoader_fileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	unsupported := filepath.Join(dir, "config.ini")
+	err := createEnvFileForTest(t, unsupported, "[server]\n")
+	require.NoError(t, err)
+
+	_, err = Load[Config](NewLoader(WithFiles(filepath.Join(dir, "missing.yaml"))))
+	assert.ErrorContains(t, err, "missing.yaml")
+
+	_, err = Load[Config](NewLoader(WithFiles(unsupported)))
+	assert.ErrorContains(t, err, `unsupported config file format ".ini"`)
+}
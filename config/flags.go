@@ -0,0 +1,150 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+)
+
+const descTagName = "desc"
+
+type (
+	// flagSource parses command-line flags derived from the fields of the config struct.
+	// Every field gets a flag named by its key, e.g. --server.port=9090.
+	flagSource struct {
+		args []string
+		// skipUnknown skips the arguments that are not flags of the config struct instead of failing.
+		skipUnknown bool
+	}
+
+	// flagValue is a flag.Value keeping the raw string, it is converted while unmarshalling
+	// like the values from the environment.
+	flagValue struct {
+		value   string
+		isBool  bool
+		isSlice bool
+	}
+)
+
+func (s *flagSource) String() string {
+	return "flags"
+}
+
+// load parses the arguments and loads the flags that were set.
+// Passing -h or --help prints the usage and returns an error wrapping flag.ErrHelp.
+// Arguments left after the flags are an error, they are usually a mistyped flag.
+func (s *flagSource) load(k *koanf.Koanf, lc *loadContext) error {
+	fs := newFlagSet(lc)
+
+	args := s.args
+	if s.skipUnknown {
+		args = knownFlagArgs(fs, args)
+	}
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("error parsing flags: %w", err)
+	}
+
+	if fs.NArg() > 0 {
+		return fmt.Errorf("error parsing flags: unexpected argument %q", fs.Arg(0))
+	}
+
+	values := make(map[string]any)
+	fs.Visit(func(f *flag.Flag) {
+		v := f.Value.(*flagValue)
+		if v.isSlice {
+			values[f.Name] = splitList(v.value)
+			return
+		}
+
+		values[f.Name] = v.value
+	})
+
+	return k.Load(confmap.Provider(values, delim), nil)
+}
+
+// knownFlagArgs returns the flags of args defined in fs with their values, and the help flags.
+// The other flags and the positional arguments are left out, as is everything after --.
+func knownFlagArgs(fs *flag.FlagSet, args []string) []string {
+	var known []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if name == arg || name == "" {
+			continue
+		}
+
+		name, _, hasValue := strings.Cut(name, "=")
+		if name == "h" || name == "help" {
+			known = append(known, arg)
+			continue
+		}
+
+		f := fs.Lookup(name)
+		if f == nil {
+			continue
+		}
+
+		known = append(known, arg)
+
+		// The value of a flag without = is the next argument, except for the boolean ones.
+		if !hasValue && !f.Value.(*flagValue).isBool && i+1 < len(args) {
+			known = append(known, args[i+1])
+			i++
+		}
+	}
+
+	return known
+}
+
+// newFlagSet creates a flag set with a flag for every field of the config struct.
+// The usage of a flag is read from the desc tag of the field, its default from the default tag.
+func newFlagSet(lc *loadContext) *flag.FlagSet {
+	fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+
+	for _, f := range fields(lc.typ) {
+		usage := f.tag.Get(descTagName)
+		if usage != "" {
+			usage += " "
+		}
+		usage += fmt.Sprintf("(env %s)", envVarName(lc.prefix, f.key))
+
+		fs.Var(&flagValue{
+			value:   f.tag.Get(defaultTagName),
+			isBool:  indirectType(f.typ).Kind() == reflect.Bool,
+			isSlice: indirectType(f.typ).Kind() == reflect.Slice,
+		}, f.key, usage)
+	}
+
+	return fs
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.value
+}
+
+func (v *flagValue) Set(s string) error {
+	v.value = s
+	return nil
+}
+
+// IsBoolFlag lets boolean fields be set with just --name.
+func (v *flagValue) IsBoolFlag() bool {
+	return v.isBool
+}
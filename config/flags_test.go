@@ -0,0 +1,94 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	flagsServer struct {
+		Host  string   `env:"host" desc:"Address to listen on" default:"0.0.0.0"`
+		Port  int      `env:"port" desc:"Port to listen on" default:"8080"`
+		Debug bool     `env:"debug" desc:"Enable debug mode"`
+		Hosts []string `env:"hosts"`
+	}
+
+	flagsConfig struct {
+		Server flagsServer `env:"server"`
+	}
+)
+
+func TestLoad_flags(t *testing.T) {
+	t.Setenv("APP_SERVER_HOST", "env")
+	t.Setenv("APP_SERVER_PORT", "1000")
+
+	l := NewLoader(
+		WithPrefix("APP_"),
+		WithOSEnv(),
+		WithFlagArgs([]string{"--server.port=9090", "--server.debug", "-server.hosts", "a, b"}),
+	)
+
+	cfg, err := Load[flagsConfig](l)
+	require.NoError(t, err)
+
+	expected := flagsConfig{
+		Server: flagsServer{
+			Host:  "env",
+			Port:  9090,
+			Debug: true,
+			Hosts: []string{"a", "b"},
+		},
+	}
+	assert.Equal(t, expected, *cfg)
+}
+
+func TestLoad_flagsErrors(t *testing.T) {
+	_, err := Load[flagsConfig](NewLoader(WithFlagArgs([]string{"--help"})))
+	assert.ErrorIs(t, err, flag.ErrHelp)
+
+	_, err = Load[flagsConfig](NewLoader(WithFlagArgs([]string{"--server.unknown=1"})))
+	assert.ErrorContains(t, err, "flag provided but not defined: -server.unknown")
+
+	_, err = Load[flagsConfig](NewLoader(WithFlagArgs([]string{"--server.port=9090", "stray"})))
+	assert.ErrorContains(t, err, `unexpected argument "stray"`)
+
+	_, err = Load[flagsConfig](NewLoader(WithFlagArgs([]string{"--", "--server.port=9090"})))
+	assert.ErrorContains(t, err, `unexpected argument "--server.port=9090"`)
+}
+
+func Test_knownFlagArgs(t *testing.T) {
+	fs := newFlagSet(&loadContext{typ: reflect.TypeFor[flagsConfig]()})
+
+	args := []string{
+		"-test.run", "TestX", "--server.port", "9090", "--server.debug", "-server.host=a",
+		"--unknown=1", "positional", "-h", "--", "--server.hosts=b",
+	}
+	expected := []string{"--server.port", "9090", "--server.debug", "-server.host=a", "-h"}
+
+	assert.Equal(t, expected, knownFlagArgs(fs, args))
+}
+
+func Test_newFlagSet_usage(t *testing.T) {
+	fs := newFlagSet(&loadContext{prefix: "APP_", typ: reflect.TypeFor[flagsConfig]()})
+
+	var buf bytes.Buffer
+	fs.SetOutput(&buf)
+	fs.PrintDefaults()
+
+	expected := "" +
+		"  -server.debug\n" +
+		"    \tEnable debug mode (env APP_SERVER_DEBUG)\n" +
+		"  -server.host value\n" +
+		"    \tAddress to listen on (env APP_SERVER_HOST) (default 0.0.0.0)\n" +
+		"  -server.hosts value\n" +
+		"    \t(env APP_SERVER_HOSTS)\n" +
+		"  -server.port value\n" +
+		"    \tPort to listen on (env APP_SERVER_PORT) (default 8080)\n"
+
+	assert.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+type (
+	// Loader loads config from an ordered list of sources.
+	//
+	// Sources are loaded in the order their options were passed to NewLoader,
+	// values from later sources override the ones from earlier sources.
+	// The default tags of the config struct are always applied first.
+	// Every call to Load starts from an empty koanf instance, so a Loader never
+	// carries keys over between calls and is safe for concurrent use.
+	Loader struct {
+		prefix     string
+		profile    string
+		profileVar string
+		sources    []source
+		validator  validator
+		dumpLogger *slog.Logger
+		strict     bool
+		decrypter  Decrypter
+	}
+
+	// source is a single layer of config.
+	source interface {
+		// String returns a human-readable name of the source used in errors.
+		String() string
+		load(k *koanf.Koanf, lc *loadContext) error
+	}
+
+	// sourceGroup is implemented by the sources made of other sources.
+	// The group is expanded on every load, so each source is reported on its own.
+	sourceGroup interface {
+		sources(lc *loadContext) []source
+	}
+
+	// loadContext holds what the sources need to know about the current load.
+	loadContext struct {
+		prefix string
+		// profile is the name of the active profile, empty when there is none.
+		profile string
+		// typ is the type of the config struct being loaded.
+		typ reflect.Type
+	}
+
+	providerSource struct {
+		name     string
+		provider koanf.Provider
+		parser   koanf.Parser
+	}
+)
+
+// NewLoader creates a Loader with the given options.
+func NewLoader(opts ...Option) *Loader {
+	l := &Loader{}
+
+	for _, opt := range opts {
+		opt.apply(l)
+	}
+
+	return l
+}
+
+// Load loads all the sources of l and unmarshals the result into a new T.
+//
+// The default tags of T are applied before any other source.
+// If the Loader has a validator, an invalid config is reported with a *ValidationError.
+func Load[T any](l *Loader) (*T, error) {
+	c, origins, err := loadConfig[T](l)
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.validate(c)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.dumpLogger != nil {
+		logSettings(l.dumpLogger, describe(l.prefix, c, origins))
+	}
+
+	return c, nil
+}
+
+// loadConfig loads and unmarshals the config without validating it.
+// It also returns the name of the source of every key.
+func loadConfig[T any](l *Loader) (*T, map[string]string, error) {
+	k, origins, err := l.load(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := unmarshalConfig[T](k, l.prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c, origins, nil
+}
+
+// load merges the defaults of t and all the sources into a fresh koanf instance.
+//
+// Each source is loaded on its own first, so its file references and encrypted values
+// can be resolved before it is merged over the previous sources. In strict mode, the keys of every source
+// are checked against the fields of t. The returned origins map every
+// key, and its parent keys, to the name of the last source that set it.
+func (l *Loader) load(t reflect.Type) (*koanf.Koanf, map[string]string, error) {
+	k := koanf.New(delim)
+	lc := &loadContext{prefix: l.prefix, profile: l.activeProfile(), typ: t}
+	origins := make(map[string]string)
+	known := fields(t)
+
+	var unknown []UnknownKey
+
+	sources := []source{&defaultsSource{}}
+	for _, s := range l.sources {
+		if g, ok := s.(sourceGroup); ok {
+			sources = append(sources, g.sources(lc)...)
+			continue
+		}
+
+		sources = append(sources, s)
+	}
+
+	for _, s := range sources {
+		sk := koanf.New(delim)
+
+		err := s.load(sk, lc)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// A remote document must not read the local files, they would end up in the config and its dumps.
+		if _, remote := s.(*remoteSource); !remote {
+			err = resolveFileRefs(sk, known)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error loading config from %s: %w", s, err)
+			}
+		}
+
+		err = decryptValues(sk, l.decrypter, known)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error loading config from %s: %w", s, err)
+		}
+
+		if l.strict {
+			unknown = append(unknown, l.unknownKeys(s, sk, lc)...)
+		}
+
+		err = k.Merge(sk)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error merging config from %s: %w", s, err)
+		}
+
+		for _, key := range sk.Keys() {
+			for {
+				origins[key] = s.String()
+
+				i := strings.LastIndex(key, delim)
+				if i < 0 {
+					break
+				}
+
+				key = key[:i]
+			}
+		}
+	}
+
+	if len(unknown) > 0 {
+		return nil, nil, &UnknownKeysError{Unknown: unknown}
+	}
+
+	return k, origins, nil
+}
+
+func (s *providerSource) String() string {
+	return s.name
+}
+
+func (s *providerSource) load(k *koanf.Koanf, _ *loadContext) error {
+	err := k.Load(s.provider, s.parser)
+	if err != nil {
+		return fmt.Errorf("error loading config from %s: %w", s, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFromEnv_callsAreIsolated(t *testing.T) {
+	dir := t.TempDir()
+
+	first := filepath.Join(dir, "first.env")
+	err := createEnvFileForTest(t, first, "SERVER_HOST=first\nSERVER_PORT=1111\n")
+	require.NoError(t, err)
+
+	second := filepath.Join(dir, "second.env")
+	err = createEnvFileForTest(t, second, "SERVER_HOST=second\n")
+	require.NoError(t, err)
+
+	cfg, err := ReadFromEnv[Config](first, "")
+	require.NoError(t, err)
+	assert.Equal(t, "first", cfg.Server.Host)
+	assert.Equal(t, 1111, cfg.Server.Port)
+
+	cfg, err = ReadFromEnv[Config](second, "")
+	require.NoError(t, err)
+	assert.Equal(t, "second", cfg.Server.Host)
+	assert.Equal(t, 0, cfg.Server.Port, "port must not leak from the previous call")
+}
+
+func TestLoader_prefixesAreIsolated(t *testing.T) {
+	t.Setenv("ONE_SERVER_HOST", "one")
+	t.Setenv("TWO_SERVER_PORT", "2222")
+
+	one, err := Load[Config](NewLoader(WithPrefix("ONE_"), WithOSEnv()))
+	require.NoError(t, err)
+
+	two, err := Load[Config](NewLoader(WithPrefix("TWO_"), WithOSEnv()))
+	require.NoError(t, err)
+
+	assert.Equal(t, Config{Server: Server{Host: "one"}}, *one)
+	assert.Equal(t, Config{Server: Server{Port: 2222}}, *two)
+}
+
+func TestLoader_sourcesOverrideInOrder(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), ".env")
+	err := createEnvFileForTest(t, fileName, "APP_SERVER_HOST=file\nAPP_SERVER_PORT=1000\n")
+	require.NoError(t, err)
+
+	t.Setenv("APP_SERVER_HOST", "env")
+
+	base := confmap.Provider(map[string]any{
+		"server.host": "base",
+		"server.port": 1,
+	}, delim)
+
+	l := NewLoader(
+		WithPrefix("APP_"),
+		WithProvider("base", base, nil),
+		WithEnvFile(fileName),
+		WithOSEnv(),
+	)
+
+	cfg, err := Load[Config](l)
+	require.NoError(t, err)
+
+	assert.Equal(t, "env", cfg.Server.Host)
+	assert.Equal(t, 1000, cfg.Server.Port)
+}
+
+func TestLoader_concurrentLoads(t *testing.T) {
+	t.Setenv("APP_SERVER_HOST", "localhost")
+	t.Setenv("APP_SERVER_PORT", "8080")
+
+	l := NewLoader(WithPrefix("APP_"), WithOSEnv())
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			cfg, err := Load[Config](l)
+			assert.NoError(t, err)
+			assert.Equal(t, Config{Server: Server{Host: "localhost", Port: 8080}}, *cfg)
+		}()
+	}
+	wg.Wait()
+}
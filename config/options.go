@@ -0,0 +1,173 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/knadh/koanf/v2"
+)
+
+type (
+	Option interface {
+		apply(*Loader)
+	}
+
+	optionFunc func(*Loader)
+)
+
+func (fn optionFunc) apply(l *Loader) {
+	fn(l)
+}
+
+// WithPrefix sets the prefix of the environment variables to read.
+// The prefix is stripped from the variable names before they are mapped to keys.
+func WithPrefix(prefix string) Option {
+	return optionFunc(func(l *Loader) {
+		l.prefix = prefix
+	})
+}
+
+// WithEnvFile adds a dotenv file source. A missing file is ignored.
+func WithEnvFile(path string) Option {
+	return optionFunc(func(l *Loader) {
+		l.sources = append(l.sources, &envFileSource{path: path})
+	})
+}
+
+// WithFiles adds YAML, JSON or TOML files as sources, the format is picked from the file extension.
+// Files are merged in the given order, so later files override earlier ones.
+// Pass them before WithEnvFile and WithOSEnv to let the environment win.
+//
+// Every file must exist, use WithOptionalFiles for files that may be missing.
+func WithFiles(paths ...string) Option {
+	return optionFunc(func(l *Loader) {
+		for _, path := range paths {
+			l.sources = append(l.sources, &fileSource{path: path})
+		}
+	})
+}
+
+// WithOptionalFiles works like WithFiles but ignores the files that don't exist.
+func WithOptionalFiles(paths ...string) Option {
+	return optionFunc(func(l *Loader) {
+		for _, path := range paths {
+			l.sources = append(l.sources, &fileSource{path: path, optional: true})
+		}
+	})
+}
+
+// WithOSEnv adds the environment variables of the process as a source.
+func WithOSEnv() Option {
+	return optionFunc(func(l *Loader) {
+		l.sources = append(l.sources, &osEnvSource{})
+	})
+}
+
+// WithProvider adds any koanf provider as a source.
+// The name is used to identify the source in errors.
+func WithProvider(name string, provider koanf.Provider, parser koanf.Parser) Option {
+	return optionFunc(func(l *Loader) {
+		l.sources = append(l.sources, &providerSource{
+			name:     name,
+			provider: provider,
+			parser:   parser,
+		})
+	})
+}
+
+// WithValidator validates the loaded config with v, usually a *validator.Validator.
+// The validate tags of the config struct are checked after all the sources are loaded.
+func WithValidator(v validator) Option {
+	return optionFunc(func(l *Loader) {
+		l.validator = v
+	})
+}
+
+// WithDumpLogger logs every setting of the config with its source through log once it is loaded,
+// the fields tagged with `secret:"true"` are redacted. See Describe.
+func WithDumpLogger(log *slog.Logger) Option {
+	return optionFunc(func(l *Loader) {
+		l.dumpLogger = log
+	})
+}
+
+// WithFlags adds the command-line flags of the process as a source, see WithFlagArgs.
+func WithFlags() Option {
+	return WithFlagArgs(os.Args[1:])
+}
+
+// WithFlagArgs adds the given command-line arguments as a source.
+//
+// A flag is derived from every field of the config struct and named by its key,
+// e.g. --server.port=9090, with the usage read from the desc tag. Pass it after
+// WithOSEnv to let flags override the environment. With -h or --help, the usage
+// of every setting is printed and Load returns an error wrapping flag.ErrHelp.
+func WithFlagArgs(args []string) Option {
+	return optionFunc(func(l *Loader) {
+		l.sources = append(l.sources, &flagSource{args: args})
+	})
+}
+
+// WithProfileEnvFiles adds the cascade of dotenv files of the active profile as sources,
+// from the lowest to the highest precedence: <base>, <base>.<profile>, <base>.local and
+// <base>.<profile>.local. For example, with the base .env and the profile production:
+// .env, .env.production, .env.local and .env.production.local.
+//
+// Without an active profile, only <base> and <base>.local are loaded. Missing files are ignored.
+func WithProfileEnvFiles(base string) Option {
+	return optionFunc(func(l *Loader) {
+		l.sources = append(l.sources, &profileEnvFiles{base: base, loader: l})
+	})
+}
+
+// WithProfile sets the active profile, see WithProfileEnvFiles.
+func WithProfile(profile string) Option {
+	return optionFunc(func(l *Loader) {
+		l.profile = profile
+	})
+}
+
+// WithProfileEnv reads the active profile from the environment variable name, e.g. APP_ENV.
+// When the variable is set, it takes precedence over WithProfile.
+func WithProfileEnv(name string) Option {
+	return optionFunc(func(l *Loader) {
+		l.profileVar = name
+	})
+}
+
+// WithStrict makes Load fail with an *UnknownKeysError when a source sets a key that
+// doesn't match any field of the config struct, like a misspelled APP_SERVR_PORT.
+// Every unknown key is reported with the closest known name as a suggestion.
+//
+// Only the variables starting with the prefix are checked, so without a prefix
+// the environment of the process is not checked at all.
+func WithStrict() Option {
+	return optionFunc(func(l *Loader) {
+		l.strict = true
+	})
+}
+
+// WithDecrypter decrypts the values starting with EncryptedPrefix, e.g. DB_PASSWORD=enc:v1:...,
+// with d, usually an *AESGCM. Values are decrypted in every source, after the file:// references
+// are read, so a secret file can hold an encrypted value too.
+//
+// Without a decrypter, Load fails on encrypted values instead of passing the ciphertext on.
+func WithDecrypter(d Decrypter) Option {
+	return optionFunc(func(l *Loader) {
+		l.decrypter = d
+	})
+}
+
+// WithRemoteJSON adds a JSON document fetched from url over HTTP as a source,
+// its keys are matched against the env tags like the keys of config files.
+//
+// Requests send the ETag of the last document in If-None-Match, the last good document
+// is used again when the server answers 304 Not Modified, fails or can't be reached.
+// Only the first load fails when the document can't be fetched. A Watcher polls the
+// document, see WithRemoteInterval. The file:// references of the document are not
+// resolved, so the server can't read the local files.
+func WithRemoteJSON(url string, opts ...RemoteOption) Option {
+	return optionFunc(func(l *Loader) {
+		l.sources = append(l.sources, newRemoteSource(url, opts...))
+	})
+}
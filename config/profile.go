@@ -0,0 +1,77 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// profileEnvFiles is the cascade of dotenv files of a profile, from the lowest to the highest precedence:
+// <base>, <base>.<profile>, <base>.local and <base>.<profile>.local.
+// Without an active profile, only <base> and <base>.local are loaded.
+type profileEnvFiles struct {
+	base   string
+	loader *Loader
+}
+
+func (s *profileEnvFiles) String() string {
+	return s.base + " cascade"
+}
+
+func (s *profileEnvFiles) sources(lc *loadContext) []source {
+	sources := make([]source, 0, 4)
+	for _, path := range s.paths(lc.profile) {
+		sources = append(sources, &envFileSource{path: path})
+	}
+
+	return sources
+}
+
+// load loads every file of the cascade, missing files are ignored.
+func (s *profileEnvFiles) load(k *koanf.Koanf, lc *loadContext) error {
+	for _, src := range s.sources(lc) {
+		err := src.load(k, lc)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *profileEnvFiles) watch(ctx context.Context, changed func(), failed func(error)) error {
+	for _, path := range s.paths(s.loader.activeProfile()) {
+		err := watchFile(ctx, path, changed, failed)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *profileEnvFiles) paths(profile string) []string {
+	if profile == "" {
+		return []string{s.base, s.base + ".local"}
+	}
+
+	return []string{
+		s.base,
+		fmt.Sprintf("%s.%s", s.base, profile),
+		s.base + ".local",
+		fmt.Sprintf("%s.%s.local", s.base, profile),
+	}
+}
+
+// activeProfile returns the profile set by the profile variable, or by WithProfile when the variable is not set.
+func (l *Loader) activeProfile() string {
+	if l.profileVar != "" {
+		if profile := os.Getenv(l.profileVar); profile != "" {
+			return profile
+		}
+	}
+
+	return l.profile
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type profileConfig struct {
+	Base            string `env:"base"`
+	Profile         string `env:"profile"`
+	Local           string `env:"local"`
+	ProfileLocal    string `env:"profilelocal"`
+	OverriddenTwice string `env:"overridden"`
+}
+
+func TestLoad_profileEnvFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, ".env")
+
+	files := map[string]string{
+		".env":                  "BASE=base\nOVERRIDDEN=base\n",
+		".env.production":       "PROFILE=production\nOVERRIDDEN=production\n",
+		".env.local":            "LOCAL=local\nOVERRIDDEN=local\n",
+		".env.production.local": "PROFILELOCAL=production.local\nOVERRIDDEN=production.local\n",
+		".env.test":             "PROFILE=test\n",
+	}
+	for name, data := range files {
+		err := createEnvFileForTest(t, filepath.Join(dir, name), data)
+		require.NoError(t, err)
+	}
+
+	t.Run("without profile", func(t *testing.T) {
+		cfg, err := Load[profileConfig](NewLoader(WithProfileEnvFiles(base)))
+		require.NoError(t, err)
+
+		expected := profileConfig{Base: "base", Local: "local", OverriddenTwice: "local"}
+		assert.Equal(t, expected, *cfg)
+	})
+
+	t.Run("profile from option", func(t *testing.T) {
+		cfg, err := Load[profileConfig](NewLoader(WithProfile("production"), WithProfileEnvFiles(base)))
+		require.NoError(t, err)
+
+		expected := profileConfig{
+			Base:            "base",
+			Profile:         "production",
+			Local:           "local",
+			ProfileLocal:    "production.local",
+			OverriddenTwice: "production.local",
+		}
+		assert.Equal(t, expected, *cfg)
+	})
+
+	t.Run("profile from variable overrides option", func(t *testing.T) {
+		t.Setenv("APP_ENV", "test")
+
+		l := NewLoader(WithProfileEnvFiles(base), WithProfile("production"), WithProfileEnv("APP_ENV"))
+
+		cfg, err := Load[profileConfig](l)
+		require.NoError(t, err)
+
+		expected := profileConfig{Base: "base", Profile: "test", Local: "local", OverriddenTwice: "local"}
+		assert.Equal(t, expected, *cfg)
+	})
+
+	t.Run("files are reported on their own", func(t *testing.T) {
+		settings, err := Describe[profileConfig](NewLoader(WithProfile("production"), WithProfileEnvFiles(base)))
+		require.NoError(t, err)
+
+		sources := make(map[string]string)
+		for _, s := range settings {
+			sources[s.Key] = s.Source
+		}
+
+		assert.Equal(t, base, sources["base"])
+		assert.Equal(t, base+".production", sources["profile"])
+		assert.Equal(t, base+".production.local", sources["overridden"])
+	})
+}
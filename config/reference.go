@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Variable documents a config field as an environment variable.
+type Variable struct {
+	// Name is the full name of the environment variable, e.g. APP_SERVER_PORT.
+	Name string
+	// Key is the key of the field in config files and flags, e.g. server.port.
+	Key string
+	// Type is the Go type of the field, e.g. time.Duration.
+	Type string
+	// Default is the value of the default tag.
+	Default string
+	// Description is the value of the desc tag.
+	Description string
+	// Required is set when the validate tag has the required rule.
+	Required bool
+	// Secret is set for the fields tagged with `secret:"true"`.
+	Secret bool
+}
+
+// Variables returns the environment variables read into T with the given prefix,
+// in the order of the struct fields. Names are derived from the env tags exactly like Load does.
+func Variables[T any](prefix string) []Variable {
+	var vars []Variable
+
+	for _, f := range fields(reflect.TypeFor[T]()) {
+		vars = append(vars, Variable{
+			Name:        envVarName(prefix, f.key),
+			Key:         f.key,
+			Type:        f.typ.String(),
+			Default:     f.tag.Get(defaultTagName),
+			Description: f.tag.Get(descTagName),
+			Required:    slices.Contains(strings.Split(f.tag.Get("validate"), ","), "required"),
+			Secret:      f.tag.Get(secretTagName) == "true",
+		})
+	}
+
+	return vars
+}
+
+// WriteEnvExample writes vars as a .env.example file.
+// Every variable is set to its default and preceded by its description and type,
+// secrets are always left empty.
+func WriteEnvExample(w io.Writer, vars []Variable) error {
+	for i, v := range vars {
+		if i > 0 {
+			_, err := fmt.Fprintln(w)
+			if err != nil {
+				return err
+			}
+		}
+
+		if v.Description != "" {
+			_, err := fmt.Fprintf(w, "# %s\n", v.Description)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := fmt.Fprintf(w, "# type: %s%s\n", v.Type, variableFlags(v))
+		if err != nil {
+			return err
+		}
+
+		value := v.Default
+		if v.Secret {
+			value = ""
+		}
+
+		_, err = fmt.Fprintf(w, "%s=%s\n", v.Name, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteMarkdown writes vars as a Markdown table, the defaults of secrets are left out.
+func WriteMarkdown(w io.Writer, vars []Variable) error {
+	_, err := fmt.Fprint(w, "| Variable | Type | Default | Description |\n| --- | --- | --- | --- |\n")
+	if err != nil {
+		return err
+	}
+
+	for _, v := range vars {
+		def := ""
+		if v.Default != "" && !v.Secret {
+			def = "`" + v.Default + "`"
+		}
+
+		description := strings.TrimSpace(v.Description + variableFlags(v))
+
+		_, err = fmt.Fprintf(w, "| `%s` | `%s` | %s | %s |\n",
+			v.Name, v.Type, escapeMarkdownCell(def), escapeMarkdownCell(description))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// variableFlags returns the notes about a variable appended to its description.
+func variableFlags(v Variable) string {
+	var flags []string
+	if v.Required {
+		flags = append(flags, "required")
+	}
+
+	if v.Secret {
+		flags = append(flags, "secret")
+	}
+
+	if len(flags) == 0 {
+		return ""
+	}
+
+	return " (" + strings.Join(flags, ", ") + ")"
+}
+
+func escapeMarkdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	referenceServer struct {
+		Host    string        `env:"host" desc:"Address to listen on" default:"0.0.0.0"`
+		Port    int           `env:"port" desc:"Port to listen on" default:"8080" validate:"required,min=1"`
+		Timeout time.Duration `env:"timeout" desc:"Read timeout | write timeout" default:"5s"`
+	}
+
+	referenceConfig struct {
+		Server   referenceServer `env:"server"`
+		Password string          `env:"password" default:"changeme" secret:"true"`
+	}
+)
+
+func TestVariables(t *testing.T) {
+	vars := Variables[referenceConfig]("APP_")
+
+	expected := []Variable{
+		{Name: "APP_SERVER_HOST", Key: "server.host", Type: "string", Default: "0.0.0.0", Description: "Address to listen on"},
+		{Name: "APP_SERVER_PORT", Key: "server.port", Type: "int", Default: "8080", Description: "Port to listen on", Required: true},
+		{Name: "APP_SERVER_TIMEOUT", Key: "server.timeout", Type: "time.Duration", Default: "5s", Description: "Read timeout | write timeout"},
+		{Name: "APP_PASSWORD", Key: "password", Type: "string", Default: "changeme", Secret: true},
+	}
+	assert.Equal(t, expected, vars)
+}
+
+func TestWriteEnvExample(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteEnvExample(&buf, Variables[referenceConfig]("APP_")))
+
+	expected := "# Address to listen on\n" +
+		"# type: string\n" +
+		"APP_SERVER_HOST=0.0.0.0\n" +
+		"\n" +
+		"# Port to listen on\n" +
+		"# type: int (required)\n" +
+		"APP_SERVER_PORT=8080\n" +
+		"\n" +
+		"# Read timeout | write timeout\n" +
+		"# type: time.Duration\n" +
+		"APP_SERVER_TIMEOUT=5s\n" +
+		"\n" +
+		"# type: string (secret)\n" +
+		"APP_PASSWORD=\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteMarkdown(&buf, Variables[referenceConfig]("APP_")))
+
+	expected := "| Variable | Type | Default | Description |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| `APP_SERVER_HOST` | `string` | `0.0.0.0` | Address to listen on |\n" +
+		"| `APP_SERVER_PORT` | `int` | `8080` | Port to listen on (required) |\n" +
+		"| `APP_SERVER_TIMEOUT` | `time.Duration` | `5s` | Read timeout \\| write timeout |\n" +
+		"| `APP_PASSWORD` | `string` |  | (secret) |\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestVariables_roundTrip(t *testing.T) {
+	type config struct {
+		Server struct {
+			ReadTimeout  time.Duration `env:"read_timeout"`
+			MaxBodySize  ByteSize      `env:"max_body_size"`
+			AllowedHosts []string      `env:"allowed_hosts"`
+		} `env:"server"`
+		LogLevel string `env:"log_level"`
+		Port     int
+	}
+
+	values := map[string]string{
+		"APP_SERVER_READ_TIMEOUT":  "7s",
+		"APP_SERVER_MAX_BODY_SIZE": "1MiB",
+		"APP_SERVER_ALLOWED_HOSTS": "a.example.com,b.example.com",
+		"APP_LOG_LEVEL":            "debug",
+		"APP_PORT":                 "8080",
+	}
+
+	vars := Variables[config]("APP_")
+	require.Len(t, vars, len(values))
+
+	for _, v := range vars {
+		value, ok := values[v.Name]
+		require.True(t, ok, "unexpected variable %s", v.Name)
+		t.Setenv(v.Name, value)
+	}
+
+	cfg, err := Load[config](NewLoader(WithPrefix("APP_"), WithOSEnv(), WithStrict()))
+	require.NoError(t, err)
+
+	assert.Equal(t, 7*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, MiB, cfg.Server.MaxBodySize)
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, cfg.Server.AllowedHosts)
+	assert.Equal(t, "debug", cfg.LogLevel)
+	assert.Equal(t, 8080, cfg.Port)
+}
@@ -0,0 +1,218 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/knadh/koanf/parsers/json"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	defaultRemoteTimeout  = 10 * time.Second
+	defaultRemoteInterval = 30 * time.Second
+)
+
+type (
+	// RemoteOption configures a remote source, see WithRemoteJSON.
+	RemoteOption interface {
+		applyRemote(*remoteSource)
+	}
+
+	remoteOptionFunc func(*remoteSource)
+
+	// remoteSource is a JSON document fetched over HTTP.
+	//
+	// The last good document is kept with its ETag: it is sent back in If-None-Match,
+	// and the document is used again when the server answers 304 Not Modified or fails.
+	remoteSource struct {
+		url      string
+		client   *http.Client
+		timeout  time.Duration
+		interval time.Duration
+		header   http.Header
+
+		mu   sync.Mutex
+		etag string
+		// last is the body of the last good response, nil until the first one.
+		last []byte
+	}
+)
+
+func (fn remoteOptionFunc) applyRemote(s *remoteSource) {
+	fn(s)
+}
+
+// WithRemoteClient sets the HTTP client used to fetch the document, http.DefaultClient by default.
+func WithRemoteClient(client *http.Client) RemoteOption {
+	return remoteOptionFunc(func(s *remoteSource) {
+		s.client = client
+	})
+}
+
+// WithRemoteTimeout sets the timeout of every request, 10 seconds by default.
+// A timeout of 0 or less keeps the default.
+func WithRemoteTimeout(timeout time.Duration) RemoteOption {
+	return remoteOptionFunc(func(s *remoteSource) {
+		s.timeout = timeout
+	})
+}
+
+// WithRemoteInterval sets how often a Watcher polls the document, 30 seconds by default.
+// An interval of 0 or less keeps the default.
+func WithRemoteInterval(interval time.Duration) RemoteOption {
+	return remoteOptionFunc(func(s *remoteSource) {
+		s.interval = interval
+	})
+}
+
+// WithRemoteHeader adds a header to every request, e.g. Authorization.
+func WithRemoteHeader(name, value string) RemoteOption {
+	return remoteOptionFunc(func(s *remoteSource) {
+		s.header.Add(name, value)
+	})
+}
+
+func newRemoteSource(url string, opts ...RemoteOption) *remoteSource {
+	s := &remoteSource{
+		url:      url,
+		client:   http.DefaultClient,
+		timeout:  defaultRemoteTimeout,
+		interval: defaultRemoteInterval,
+		header:   make(http.Header),
+	}
+
+	for _, opt := range opts {
+		opt.applyRemote(s)
+	}
+
+	if s.timeout <= 0 {
+		s.timeout = defaultRemoteTimeout
+	}
+
+	if s.interval <= 0 {
+		s.interval = defaultRemoteInterval
+	}
+
+	return s
+}
+
+func (s *remoteSource) String() string {
+	return s.url
+}
+
+// load fetches the document and loads it into k.
+// When the request fails after a good document was fetched, that document is loaded instead.
+func (s *remoteSource) load(k *koanf.Koanf, _ *loadContext) error {
+	body, _, err := s.fetch(context.Background())
+	if body == nil {
+		return fmt.Errorf("error loading config from %s: %w", s, err)
+	}
+
+	values, err := json.Parser().Unmarshal(body)
+	if err != nil {
+		return fmt.Errorf("error loading config from %s: %w", s, err)
+	}
+
+	return k.Load(confmap.Provider(values, delim), nil)
+}
+
+// watch polls the document every interval and calls changed when a new one is fetched.
+func (s *remoteSource) watch(ctx context.Context, changed func(), failed func(error)) error {
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_, updated, err := s.fetch(ctx)
+				if err != nil {
+					failed(fmt.Errorf("error polling %s: %w", s, err))
+					continue
+				}
+
+				if updated {
+					changed()
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// fetch returns the current document and whether it differs from the previous one.
+// If the request fails, the last good document is returned with the error,
+// the document is nil when there is none.
+func (s *remoteSource) fetch(ctx context.Context) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, etag, err := s.request(ctx, s.etag)
+	if err != nil {
+		return s.last, false, err
+	}
+
+	// 304 Not Modified.
+	if body == nil {
+		return s.last, false, nil
+	}
+
+	updated := !bytes.Equal(body, s.last)
+	s.etag, s.last = etag, body
+
+	return body, updated, nil
+}
+
+// request gets the document, conditionally when etag is set.
+// It returns a nil body when the server answers 304 Not Modified.
+func (s *remoteSource) request(ctx context.Context, etag string) ([]byte, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	req.Header = s.header.Clone()
+	req.Header.Set("Accept", "application/json")
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && etag != "":
+		return nil, "", nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading response: %w", err)
+	}
+
+	// A broken document must not replace the last good one.
+	_, err = json.Parser().Unmarshal(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid document: %w", err)
+	}
+
+	return body, resp.Header.Get("ETag"), nil
+}
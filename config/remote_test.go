@@ -0,0 +1,180 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// remoteServer serves a JSON document with an ETag derived from its version.
+type remoteServer struct {
+	mu          sync.Mutex
+	document    string
+	version     int
+	down        bool
+	requests    int
+	notModified int
+}
+
+func (s *remoteServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+
+	if s.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	etag := `"v` + strconv.Itoa(s.version) + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	_, _ = w.Write([]byte(s.document))
+}
+
+func (s *remoteServer) set(document string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.document = document
+	s.version++
+}
+
+func (s *remoteServer) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.down = down
+}
+
+func TestLoad_remoteJSON(t *testing.T) {
+	rs := &remoteServer{}
+	rs.set(`{"server": {"host": "remote", "port": 9000}}`)
+
+	srv := httptest.NewServer(rs)
+	defer srv.Close()
+
+	t.Setenv("SERVER_PORT", "9090")
+
+	l := NewLoader(WithRemoteJSON(srv.URL), WithOSEnv())
+
+	cfg, err := Load[Config](l)
+	require.NoError(t, err)
+	assert.Equal(t, "remote", cfg.Server.Host)
+	assert.Equal(t, 9090, cfg.Server.Port, "later sources override the remote document")
+
+	t.Run("not modified", func(t *testing.T) {
+		cfg, err := Load[Config](l)
+		require.NoError(t, err)
+		assert.Equal(t, "remote", cfg.Server.Host)
+		assert.Equal(t, 1, rs.notModified)
+	})
+
+	t.Run("last good document when the server is down", func(t *testing.T) {
+		rs.setDown(true)
+		defer rs.setDown(false)
+
+		cfg, err := Load[Config](l)
+		require.NoError(t, err)
+		assert.Equal(t, "remote", cfg.Server.Host)
+	})
+
+	t.Run("invalid document keeps the last good one", func(t *testing.T) {
+		rs.set(`{"server":`)
+
+		cfg, err := Load[Config](l)
+		require.NoError(t, err)
+		assert.Equal(t, "remote", cfg.Server.Host)
+	})
+}
+
+func TestLoad_remoteJSONFileRefsAreNotResolved(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("local secret"), 0o600))
+
+	rs := &remoteServer{}
+	rs.set(`{"server": {"host": "file://` + filepath.ToSlash(secret) + `"}}`)
+
+	srv := httptest.NewServer(rs)
+	defer srv.Close()
+
+	cfg, err := Load[Config](NewLoader(WithRemoteJSON(srv.URL)))
+	require.NoError(t, err)
+	assert.Equal(t, "file://"+filepath.ToSlash(secret), cfg.Server.Host)
+}
+
+func TestNewRemoteSource_invalidDurations(t *testing.T) {
+	s := newRemoteSource("http://localhost", WithRemoteInterval(0), WithRemoteTimeout(-time.Second))
+	assert.Equal(t, defaultRemoteInterval, s.interval)
+	assert.Equal(t, defaultRemoteTimeout, s.timeout)
+}
+
+func TestLoad_remoteJSONUnavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	_, err := Load[Config](NewLoader(WithRemoteJSON(srv.URL, WithRemoteTimeout(10*time.Millisecond))))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWatcher_pollsRemoteJSON(t *testing.T) {
+	rs := &remoteServer{}
+	rs.set(`{"server": {"host": "before"}}`)
+
+	srv := httptest.NewServer(rs)
+	defer srv.Close()
+
+	l := NewLoader(WithRemoteJSON(srv.URL, WithRemoteInterval(10*time.Millisecond)))
+
+	w, err := NewWatcher[Config](l)
+	require.NoError(t, err)
+
+	errs := make(chan error, 10)
+	w.OnError(func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		assert.NoError(t, w.Watch(ctx))
+	}()
+
+	rs.set(`{"server": {"host": "after"}}`)
+
+	require.Eventually(t, func() bool {
+		return w.Current().Server.Host == "after"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	rs.setDown(true)
+
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "unexpected status 503 Service Unavailable")
+	case <-time.After(5 * time.Second):
+		t.Fatal("polling error not reported")
+	}
+
+	assert.Equal(t, "after", w.Current().Server.Host)
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	// secretFileSuffix marks an environment variable holding the path of the file with the actual value.
+	secretFileSuffix = "_FILE"
+	// fileRefScheme marks a value holding the path of the file with the actual value.
+	fileRefScheme = "file://"
+)
+
+// resolveSecretFiles replaces the variables following the _FILE convention with the
+// content of their files: APP_DB_PASSWORD_FILE=/run/secrets/db sets APP_DB_PASSWORD.
+//
+// Only variables whose name without the suffix maps to a field of the config struct are
+// resolved, so APP_LOG_FILE is left as is when the struct has a log.file field.
+func resolveSecretFiles(vars map[string]string, lc *loadContext) (map[string]string, error) {
+	toKey := envKeyFunc(lc.prefix, lc.typ)
+
+	keys := make(map[string]bool)
+	for _, f := range fields(lc.typ) {
+		keys[f.key] = true
+	}
+
+	resolved := make(map[string]string, len(vars))
+	for name, value := range vars {
+		target, isRef := strings.CutSuffix(name, secretFileSuffix)
+		if !isRef || !strings.HasPrefix(name, lc.prefix) || keys[toKey(name)] || !keys[toKey(target)] {
+			resolved[name] = value
+			continue
+		}
+
+		if _, ok := vars[target]; ok {
+			return nil, fmt.Errorf("both %s and %s are set, only one of them is allowed", target, name)
+		}
+
+		content, err := readSecretFile(value)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", name, err)
+		}
+
+		resolved[target] = content
+	}
+
+	return resolved, nil
+}
+
+// resolveFileRefs replaces the string values of k starting with file:// with the
+// content of the file they point to, e.g. file:///run/secrets/db.
+//
+// Only the keys of the known fields are resolved, the other values, like the unrelated
+// variables of the environment, are left as is.
+func resolveFileRefs(k *koanf.Koanf, known []field) error {
+	for key, value := range k.All() {
+		s, ok := value.(string)
+		if !ok || !knownKey(known, key) {
+			continue
+		}
+
+		path, isRef := strings.CutPrefix(s, fileRefScheme)
+		if !isRef {
+			continue
+		}
+
+		content, err := readSecretFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading %s from %s: %w", key, s, err)
+		}
+
+		err = k.Set(key, content)
+		if err != nil {
+			return fmt.Errorf("error setting %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// readSecretFile reads the file at path without the trailing newlines,
+// which are usually added by editors and `echo` but aren't part of the secret.
+func readSecretFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	secretsDB struct {
+		User     string `env:"user"`
+		Password string `env:"password"`
+	}
+
+	secretsLog struct {
+		File string `env:"file"`
+	}
+
+	secretsConfig struct {
+		DB  secretsDB  `env:"db"`
+		Log secretsLog `env:"log"`
+	}
+)
+
+func TestLoad_secretFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	secret := filepath.Join(dir, "db_password")
+	err := createEnvFileForTest(t, secret, "s3cr3t\n")
+	require.NoError(t, err)
+
+	user := filepath.Join(dir, "db_user")
+	err = createEnvFileForTest(t, user, "admin\r\n")
+	require.NoError(t, err)
+
+	t.Run("_FILE variable", func(t *testing.T) {
+		t.Setenv("APP_DB_PASSWORD_FILE", secret)
+
+		cfg, err := Load[secretsConfig](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", cfg.DB.Password)
+	})
+
+	t.Run("_FILE variable in dotenv file", func(t *testing.T) {
+		envFile := filepath.Join(dir, ".env")
+		err := createEnvFileForTest(t, envFile, "APP_DB_PASSWORD_FILE="+secret+"\n")
+		require.NoError(t, err)
+
+		cfg, err := Load[secretsConfig](NewLoader(WithPrefix("APP_"), WithEnvFile(envFile)))
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", cfg.DB.Password)
+	})
+
+	t.Run("file reference", func(t *testing.T) {
+		t.Setenv("APP_DB_USER", "file://"+user)
+
+		cfg, err := Load[secretsConfig](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+		require.NoError(t, err)
+		assert.Equal(t, "admin", cfg.DB.User)
+	})
+
+	t.Run("file reference in config file", func(t *testing.T) {
+		fileName := filepath.Join(dir, "config.yaml")
+		err := createEnvFileForTest(t, fileName, "db:\n  password: file://"+secret+"\n")
+		require.NoError(t, err)
+
+		cfg, err := Load[secretsConfig](NewLoader(WithFiles(fileName)))
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", cfg.DB.Password)
+	})
+
+	t.Run("field named file is not a reference", func(t *testing.T) {
+		t.Setenv("APP_LOG_FILE", "/var/log/app.log")
+
+		cfg, err := Load[secretsConfig](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+		require.NoError(t, err)
+		assert.Equal(t, "/var/log/app.log", cfg.Log.File)
+	})
+
+	t.Run("unreadable file", func(t *testing.T) {
+		t.Setenv("APP_DB_PASSWORD_FILE", filepath.Join(dir, "missing"))
+
+		_, err := Load[secretsConfig](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+		assert.ErrorContains(t, err, "error reading APP_DB_PASSWORD_FILE")
+		assert.ErrorContains(t, err, "missing")
+	})
+
+	t.Run("unreadable file reference", func(t *testing.T) {
+		t.Setenv("APP_DB_USER", "file://"+filepath.Join(dir, "missing"))
+
+		_, err := Load[secretsConfig](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+		assert.ErrorContains(t, err, "error reading db.user from file://")
+	})
+
+	t.Run("value and _FILE variable both set", func(t *testing.T) {
+		t.Setenv("APP_DB_PASSWORD", "plain")
+		t.Setenv("APP_DB_PASSWORD_FILE", secret)
+
+		_, err := Load[secretsConfig](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+		assert.ErrorContains(t, err, "both APP_DB_PASSWORD and APP_DB_PASSWORD_FILE are set")
+	})
+}
+
+func TestLoad_unrelatedFileRefsAreIgnored(t *testing.T) {
+	t.Setenv("MAVEN_REPO", "file:///nonexistent/repo")
+	t.Setenv("SERVER_HOST", "localhost")
+
+	cfg, err := Load[Config](NewLoader(WithEnvFile(".env"), WithOSEnv()))
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", cfg.Server.Host)
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+type (
+	// UnknownKeysError is returned by Load in strict mode when a source sets keys
+	// that don't match any field of the config struct.
+	UnknownKeysError struct {
+		Unknown []UnknownKey
+	}
+
+	// UnknownKey is a key set by a source that doesn't match any field.
+	UnknownKey struct {
+		// Name is the environment variable, e.g. APP_SERVR_PORT, for the dotenv files and
+		// the environment, the key, e.g. servr.port, for the other sources.
+		Name string
+		// Source is the name of the source that set the key.
+		Source string
+		// Suggestion is the closest known name, empty when none is close enough.
+		Suggestion string
+	}
+)
+
+// Error lists every unknown key with its source and the suggestion, if any.
+func (e *UnknownKeysError) Error() string {
+	var b strings.Builder
+	b.WriteString("unknown config keys:")
+
+	for _, u := range e.Unknown {
+		_, _ = fmt.Fprintf(&b, "\n\t%s (from %s)", u.Name, u.Source)
+		if u.Suggestion != "" {
+			_, _ = fmt.Fprintf(&b, ", did you mean %s?", u.Suggestion)
+		}
+	}
+
+	return b.String()
+}
+
+// unknownKeys returns the keys of k loaded from the source s that don't match any field.
+func (l *Loader) unknownKeys(s source, k *koanf.Koanf, lc *loadContext) []UnknownKey {
+	_, isEnv := s.(*envFileSource)
+	if _, ok := s.(*osEnvSource); ok {
+		// Without a prefix, the environment of the process holds every variable of the system.
+		if lc.prefix == "" {
+			return nil
+		}
+
+		isEnv = true
+	}
+
+	known := fields(lc.typ)
+	toKey := envKeyFunc(lc.prefix, lc.typ)
+
+	var unknown []UnknownKey
+	for _, key := range k.Keys() {
+		if isEnv && l.profileVar != "" && key == toKey(l.profileVar) {
+			continue
+		}
+
+		if knownKey(known, key) {
+			continue
+		}
+
+		name := key
+		candidates := make([]string, 0, len(known))
+		for _, f := range known {
+			candidates = append(candidates, f.key)
+		}
+
+		if isEnv {
+			name = envVarName(lc.prefix, key)
+			for i, c := range candidates {
+				candidates[i] = envVarName(lc.prefix, c)
+			}
+		}
+
+		unknown = append(unknown, UnknownKey{
+			Name:       name,
+			Source:     s.String(),
+			Suggestion: closest(name, candidates),
+		})
+	}
+
+	return unknown
+}
+
+// knownKey reports whether key is the key of one of the fields, or an element of a map field.
+func knownKey(known []field, key string) bool {
+	return slices.ContainsFunc(known, func(f field) bool {
+		return key == f.key || strings.HasPrefix(key, f.key+delim)
+	})
+}
+
+// closest returns the candidate with the smallest edit distance to name,
+// or an empty string when even the closest one differs in more than a third of name.
+func closest(name string, candidates []string) string {
+	best, bestDistance := "", len(name)/3+1
+
+	for _, c := range candidates {
+		d := levenshtein(strings.ToLower(name), strings.ToLower(c))
+		if d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+
+	return best
+}
+
+// levenshtein returns the number of single-byte insertions, deletions and substitutions
+// needed to turn a into b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type strictConfig struct {
+	Server Server            `env:"server"`
+	Labels map[string]string `env:"labels"`
+}
+
+func TestLoad_withStrict(t *testing.T) {
+	t.Run("known keys", func(t *testing.T) {
+		t.Setenv("APP_SERVER_PORT", "8080")
+		t.Setenv("APP_LABELS_TEAM", "core")
+		t.Setenv("APP_ENV", "production")
+
+		l := NewLoader(WithPrefix("APP_"), WithOSEnv(), WithProfileEnv("APP_ENV"), WithStrict())
+
+		cfg, err := Load[strictConfig](l)
+		require.NoError(t, err)
+		assert.Equal(t, 8080, cfg.Server.Port)
+		assert.Equal(t, map[string]string{"team": "core"}, cfg.Labels)
+	})
+
+	t.Run("unknown keys are reported with suggestions", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "config.yaml")
+		err := createEnvFileForTest(t, fileName, "server:\n  hots: localhost\n")
+		require.NoError(t, err)
+
+		t.Setenv("APP_SERVR_PORT", "8080")
+		t.Setenv("APP_COMPLETELY_DIFFERENT", "x")
+
+		_, err = Load[strictConfig](NewLoader(WithPrefix("APP_"), WithFiles(fileName), WithOSEnv(), WithStrict()))
+
+		var unknownErr *UnknownKeysError
+		require.ErrorAs(t, err, &unknownErr)
+
+		assert.ElementsMatch(t, []UnknownKey{
+			{Name: "server.hots", Source: fileName, Suggestion: "server.host"},
+			{Name: "APP_SERVR_PORT", Source: "environment", Suggestion: "APP_SERVER_PORT"},
+			{Name: "APP_COMPLETELY_DIFFERENT", Source: "environment"},
+		}, unknownErr.Unknown)
+		assert.Contains(t, err.Error(), "APP_SERVR_PORT (from environment), did you mean APP_SERVER_PORT?")
+	})
+
+	t.Run("underscored keys are known", func(t *testing.T) {
+		type config struct {
+			Server struct {
+				ReadTimeout string `env:"read_timeout"`
+			} `env:"server"`
+		}
+
+		t.Setenv("APP_SERVER_READ_TIMEOUT", "5s")
+		t.Setenv("APP_SERVER_READ_TIMOUT", "5s")
+
+		_, err := Load[config](NewLoader(WithPrefix("APP_"), WithOSEnv(), WithStrict()))
+
+		var unknownErr *UnknownKeysError
+		require.ErrorAs(t, err, &unknownErr)
+		assert.Equal(t, []UnknownKey{
+			{Name: "APP_SERVER_READ_TIMOUT", Source: "environment", Suggestion: "APP_SERVER_READ_TIMEOUT"},
+		}, unknownErr.Unknown)
+	})
+
+	t.Run("environment without prefix is not checked", func(t *testing.T) {
+		t.Setenv("SERVR_PORT", "8080")
+
+		_, err := Load[strictConfig](NewLoader(WithOSEnv(), WithStrict()))
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown keys are ignored without strict", func(t *testing.T) {
+		t.Setenv("APP_SERVR_PORT", "8080")
+
+		_, err := Load[strictConfig](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+		require.NoError(t, err)
+	})
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("port", "port"))
+	assert.Equal(t, 1, levenshtein("servr", "server"))
+	assert.Equal(t, 2, levenshtein("hots", "host"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+	assert.Equal(t, 4, levenshtein("", "port"))
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	validatorpkg "github.com/pushkar-anand/build-with-go/validator"
+)
+
+type (
+	// validator validates the loaded config, it is implemented by *validator.Validator.
+	validator interface {
+		ValidateStruct(context.Context, any) (*validatorpkg.Result, error)
+	}
+
+	// ValidationError is returned by Load when the loaded config fails validation.
+	ValidationError struct {
+		// Failed maps the full name of each invalid environment variable, e.g. APP_SERVER_PORT, to the reason.
+		Failed map[string]validatorpkg.Reason
+	}
+)
+
+// Error lists every invalid environment variable with the reason, sorted by name.
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Failed))
+	for name := range e.Failed {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	b.WriteString("invalid config:")
+
+	for _, name := range names {
+		reason := e.Failed[name]
+		_, _ = fmt.Fprintf(&b, "\n\t%s: %s (value: %v)", name, reason.Message, reason.Value)
+	}
+
+	return b.String()
+}
+
+// validate runs c through the validator of the Loader, if any.
+func (l *Loader) validate(c any) error {
+	if l.validator == nil {
+		return nil
+	}
+
+	result, err := l.validator.ValidateStruct(context.Background(), c)
+	if err != nil {
+		return fmt.Errorf("error validating config: %w", err)
+	}
+
+	if result.Valid {
+		return nil
+	}
+
+	keys := make(map[string]string)
+	for _, f := range fields(reflect.TypeOf(c)) {
+		keys[f.path] = f.key
+	}
+
+	failed := make(map[string]validatorpkg.Reason, len(result.Failed))
+	for name, reason := range result.Failed {
+		// Elements of slices and maps are reported by the field holding them.
+		path, _, _ := strings.Cut(reason.Path, "[")
+
+		key, ok := keys[path]
+		if !ok {
+			key = strings.ToLower(name)
+		}
+
+		failed[envVarName(l.prefix, key)] = reason
+	}
+
+	return &ValidationError{Failed: failed}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"testing"
+
+	validatorpkg "github.com/pushkar-anand/build-with-go/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	validatedServer struct {
+		Host string `env:"host" validate:"required"`
+		Port int    `env:"port" validate:"min=1,max=65535"`
+	}
+
+	validatedDB struct {
+		URL   string   `env:"url" validate:"required"`
+		Hosts []string `env:"hosts" validate:"dive,hostname"`
+	}
+
+	validatedConfig struct {
+		Server validatedServer `env:"server"`
+		DB     validatedDB     `env:"db"`
+	}
+)
+
+func TestLoad_withValidator(t *testing.T) {
+	v, err := validatorpkg.New()
+	require.NoError(t, err)
+
+	t.Run("valid config", func(t *testing.T) {
+		t.Setenv("APP_SERVER_HOST", "localhost")
+		t.Setenv("APP_SERVER_PORT", "8080")
+		t.Setenv("APP_DB_URL", "postgres://localhost/app")
+
+		cfg, err := Load[validatedConfig](NewLoader(WithPrefix("APP_"), WithOSEnv(), WithValidator(v)))
+		require.NoError(t, err)
+		assert.Equal(t, 8080, cfg.Server.Port)
+	})
+
+	t.Run("every invalid variable is reported", func(t *testing.T) {
+		t.Setenv("APP_SERVER_PORT", "70000")
+		t.Setenv("APP_DB_HOSTS", "not a host")
+
+		_, err := Load[validatedConfig](NewLoader(WithPrefix("APP_"), WithOSEnv(), WithValidator(v)))
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+
+		assert.Len(t, validationErr.Failed, 4)
+		assert.Equal(t, "required", validationErr.Failed["APP_SERVER_HOST"].Rule)
+		assert.Equal(t, "max", validationErr.Failed["APP_SERVER_PORT"].Rule)
+		assert.Equal(t, "required", validationErr.Failed["APP_DB_URL"].Rule)
+		assert.Equal(t, "hostname", validationErr.Failed["APP_DB_HOSTS"].Rule)
+
+		assert.Equal(t, "invalid config:\n"+
+			"\tAPP_DB_HOSTS: Hosts[0] failed validation for rule: hostname (value: not a host)\n"+
+			"\tAPP_DB_URL: URL is required (value: )\n"+
+			"\tAPP_SERVER_HOST: Host is required (value: )\n"+
+			"\tAPP_SERVER_PORT: Port must not exceed 65535 (value: 70000)",
+			err.Error())
+	})
+
+	t.Run("no validation without validator", func(t *testing.T) {
+		_, err := Load[validatedConfig](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+		assert.NoError(t, err)
+	})
+}
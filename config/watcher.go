@@ -0,0 +1,246 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce is how long the Watcher waits after a change before reloading,
+// editors and Kubernetes ConfigMap updates touch a file several times in a row.
+const reloadDebounce = 100 * time.Millisecond
+
+type (
+	// Watcher keeps a config of type T up to date with its sources.
+	//
+	// The config is loaded through the same Loader pipeline as Load. When one of the
+	// watched sources changes, the config is loaded again into a fresh *T and published
+	// atomically, subscribers are notified with the old and the new value.
+	// If a reload fails, the last good config is kept and the error is reported to the
+	// error handler.
+	Watcher[T any] struct {
+		loader  *Loader
+		current atomic.Pointer[T]
+
+		// reloadMu serializes reloads so subscribers see the changes in order.
+		reloadMu sync.Mutex
+
+		mu          sync.Mutex
+		nextID      int
+		subscribers map[int]func(old, new *T)
+		onError     func(error)
+	}
+
+	// notifier is implemented by the sources that can report changes.
+	notifier interface {
+		// watch starts watching the source in the background until ctx is done.
+		// changed is called on every change, failed on errors after the watch has started.
+		watch(ctx context.Context, changed func(), failed func(error)) error
+	}
+)
+
+// NewWatcher loads the config from l and returns a Watcher holding it.
+// It fails if the initial load fails.
+func NewWatcher[T any](l *Loader) (*Watcher[T], error) {
+	c, err := Load[T](l)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher[T]{
+		loader:      l,
+		subscribers: make(map[int]func(old, new *T)),
+	}
+	w.current.Store(c)
+
+	return w, nil
+}
+
+// Current returns the latest successfully loaded config.
+// The returned value must not be modified, it is shared with the other readers.
+func (w *Watcher[T]) Current() *T {
+	return w.current.Load()
+}
+
+// Subscribe registers fn to be called every time a new config is published.
+// Subscribers are called one at a time, in the order of the reloads.
+// The returned function removes the subscription.
+func (w *Watcher[T]) Subscribe(fn func(old, new *T)) (unsubscribe func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = fn
+
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		delete(w.subscribers, id)
+	}
+}
+
+// OnError sets the function called when a reload or a watch fails.
+func (w *Watcher[T]) OnError(fn func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.onError = fn
+}
+
+// Reload loads the config again and publishes it if it has changed.
+// On error, the current config is kept.
+func (w *Watcher[T]) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	c, err := Load[T](w.loader)
+	if err != nil {
+		err = fmt.Errorf("error reloading config: %w", err)
+		w.reportError(err)
+		return err
+	}
+
+	old := w.current.Load()
+	if reflect.DeepEqual(old, c) {
+		return nil
+	}
+
+	w.current.Store(c)
+
+	w.mu.Lock()
+	subscribers := make([]func(old, new *T), 0, len(w.subscribers))
+	for id := range w.nextID {
+		if fn, ok := w.subscribers[id]; ok {
+			subscribers = append(subscribers, fn)
+		}
+	}
+	w.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(old, c)
+	}
+
+	return nil
+}
+
+// Watch watches the sources of the Loader and reloads the config when they change.
+//
+// It blocks until the context is canceled.
+func (w *Watcher[T]) Watch(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	changes := make(chan struct{}, 1)
+	changed := func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+
+	for _, s := range w.loader.sources {
+		n, ok := s.(notifier)
+		if !ok {
+			continue
+		}
+
+		err := n.watch(ctx, changed, w.reportError)
+		if err != nil {
+			return err
+		}
+	}
+
+	timer := time.NewTimer(reloadDebounce)
+	timer.Stop()
+
+	// pending is set while a reload is scheduled, the changes seen in the meantime
+	// are picked up by that reload.
+	pending := false
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changes:
+			if !pending {
+				pending = true
+				timer.Reset(reloadDebounce)
+			}
+		case <-timer.C:
+			pending = false
+
+			// The error is already reported, the watcher keeps going with the last good config.
+			_ = w.Reload()
+		}
+	}
+}
+
+func (w *Watcher[T]) reportError(err error) {
+	w.mu.Lock()
+	onError := w.onError
+	w.mu.Unlock()
+
+	if onError != nil {
+		onError(err)
+	}
+}
+
+// watchFile calls changed whenever the file at path is written, created or replaced.
+//
+// The parent directory is watched instead of the file, so the file may be missing
+// when the watch starts and atomic replacements, like the symlink swaps done for
+// Kubernetes ConfigMaps, are picked up.
+func watchFile(ctx context.Context, path string, changed func(), failed func(error)) error {
+	path = filepath.Clean(path)
+
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error watching %s: %w", path, err)
+	}
+
+	err = fw.Add(filepath.Dir(path))
+	if err != nil {
+		_ = fw.Close()
+		return fmt.Errorf("error watching %s: %w", path, err)
+	}
+
+	// A missing file resolves to an empty path, its creation is seen as a change.
+	realPath, _ := filepath.EvalSymlinks(path)
+
+	go func() {
+		defer func() { _ = fw.Close() }()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-fw.Events:
+				if !ok {
+					return
+				}
+
+				curPath, _ := filepath.EvalSymlinks(path)
+				if filepath.Clean(event.Name) == path || curPath != realPath {
+					realPath = curPath
+					changed()
+				}
+			case err, ok := <-fw.Errors:
+				if !ok {
+					return
+				}
+
+				failed(fmt.Errorf("error watching %s: %w", path, err))
+			}
+		}
+	}()
+
+	return nil
+}
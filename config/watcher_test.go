@@ -0,0 +1,113 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_reloadsOnFileChange(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "config.yaml")
+	err := createEnvFileForTest(t, fileName, "server:\n  host: before\n  port: 1000\n")
+	require.NoError(t, err)
+
+	w, err := NewWatcher[Config](NewLoader(WithFiles(fileName)))
+	require.NoError(t, err)
+	assert.Equal(t, "before", w.Current().Server.Host)
+
+	var (
+		mu       sync.Mutex
+		received [][2]*Config
+	)
+	w.Subscribe(func(old, new *Config) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, [2]*Config{old, new})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		assert.NoError(t, w.Watch(ctx))
+	}()
+
+	// Keep replacing the file until the watcher has started and picked up the change,
+	// writing the same content again doesn't publish a new config. The file is replaced
+	// atomically, so a reload never reads it half written.
+	tmpName := fileName + ".tmp"
+	require.Eventually(t, func() bool {
+		err := createEnvFileForTest(t, tmpName, "server:\n  host: after\n  port: 1000\n")
+		require.NoError(t, err)
+		require.NoError(t, os.Rename(tmpName, fileName))
+
+		return w.Current().Server.Host == "after"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, received, 1)
+	assert.Equal(t, "before", received[0][0].Server.Host)
+	assert.Equal(t, "after", received[0][1].Server.Host)
+}
+
+func TestWatcher_failedReloadKeepsLastGoodConfig(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "config.yaml")
+	err := createEnvFileForTest(t, fileName, "server:\n  port: 1000\n")
+	require.NoError(t, err)
+
+	w, err := NewWatcher[Config](NewLoader(WithFiles(fileName)))
+	require.NoError(t, err)
+
+	var reported error
+	w.OnError(func(err error) {
+		reported = err
+	})
+
+	w.Subscribe(func(_, _ *Config) {
+		t.Error("subscriber must not be called on a failed reload")
+	})
+
+	err = createEnvFileForTest(t, fileName, "server:\n  port: [not a port\n")
+	require.NoError(t, err)
+
+	err = w.Reload()
+	require.Error(t, err)
+	assert.Equal(t, err, reported)
+	assert.Equal(t, 1000, w.Current().Server.Port)
+}
+
+func TestWatcher_unsubscribe(t *testing.T) {
+	t.Setenv("APP_SERVER_PORT", "1000")
+
+	w, err := NewWatcher[Config](NewLoader(WithPrefix("APP_"), WithOSEnv()))
+	require.NoError(t, err)
+
+	calls := 0
+	unsubscribe := w.Subscribe(func(_, _ *Config) {
+		calls++
+	})
+
+	t.Setenv("APP_SERVER_PORT", "2000")
+	require.NoError(t, w.Reload())
+
+	unsubscribe()
+
+	t.Setenv("APP_SERVER_PORT", "3000")
+	require.NoError(t, w.Reload())
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 3000, w.Current().Server.Port)
+}
+
+func TestNewWatcher_initialLoadError(t *testing.T) {
+	_, err := NewWatcher[Config](NewLoader(WithFiles(filepath.Join(t.TempDir(), "missing.yaml"))))
+	assert.Error(t, err)
+}
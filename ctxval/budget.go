@@ -0,0 +1,20 @@
+package ctxval
+
+import (
+	"context"
+	"time"
+)
+
+// timeBudgetKey is not registered: the budget belongs to the request, a detached context has its own.
+var timeBudgetKey = NewKey[time.Duration]("time_budget")
+
+// WithTimeBudget adds the time budget of a request, the time it was given to complete, to the given context.
+func WithTimeBudget(ctx context.Context, budget time.Duration) context.Context {
+	return timeBudgetKey.With(ctx, budget)
+}
+
+// TimeBudgetFromContext extracts the time budget of the request from the context, if any.
+// The time left is given by the deadline of the context.
+func TimeBudgetFromContext(ctx context.Context) (time.Duration, bool) {
+	return timeBudgetKey.From(ctx)
+}
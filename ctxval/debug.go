@@ -0,0 +1,17 @@
+package ctxval
+
+import "context"
+
+// debugKey is not registered: the flag changes how a request is logged, it is not logged itself.
+var debugKey = NewKey[bool]("debug")
+
+// WithDebug flags the given context for debugging, e.g. to log every record of a request.
+func WithDebug(ctx context.Context, debug bool) context.Context {
+	return debugKey.With(ctx, debug)
+}
+
+// DebugFromContext reports whether the context is flagged for debugging.
+func DebugFromContext(ctx context.Context) bool {
+	debug, _ := debugKey.From(ctx)
+	return debug
+}
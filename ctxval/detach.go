@@ -0,0 +1,59 @@
+package ctxval
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"time"
+)
+
+// Detach returns a new context for work that outlives ctx, like background work started by a request.
+//
+// The new context holds the values of all the registered keys of ctx, like the request ID,
+// the principal and the tenant, so the logs of the work can be tied back to the request.
+// It is not canceled with ctx and has no other values of ctx: values stored under other keys,
+// like the trace span of a tracing library, are not copied. Store them with a registered Key
+// to carry them over. When timeout is positive, the new context is canceled after it.
+func Detach(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	detached := context.Background()
+	for _, k := range registeredKeys() {
+		detached = k.copyValue(detached, ctx)
+	}
+
+	if timeout > 0 {
+		return context.WithTimeout(detached, timeout)
+	}
+
+	return context.WithCancel(detached)
+}
+
+// Go runs fn with ctx in a new goroutine. A panic in fn is recovered and logged at error
+// level with log, through ctx, so a logger created by logger.New adds the registered values
+// of ctx, like the request ID, to the record. A nil log uses the default slog logger.
+//
+// Use it with Detach to run background work from a request:
+//
+//	ctx, cancel := ctxval.Detach(r.Context(), time.Minute)
+//	ctxval.Go(ctx, log, func(ctx context.Context) {
+//		defer cancel()
+//		sendEmail(ctx, order)
+//	})
+func Go(ctx context.Context, log *slog.Logger, fn func(ctx context.Context)) {
+	if log == nil {
+		log = slog.Default()
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.ErrorContext(ctx, "panic in background goroutine",
+					slog.String("panic", fmt.Sprint(r)),
+					slog.String("stack", string(debug.Stack())),
+				)
+			}
+		}()
+
+		fn(ctx)
+	}()
+}
@@ -0,0 +1,104 @@
+package ctxval
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type otherKey struct{}
+
+func TestDetach(t *testing.T) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	parent = WithRequestID(parent, "req-1")
+	parent = WithTenant(parent, "acme")
+	parent = context.WithValue(parent, otherKey{}, "unregistered")
+
+	ctx, cancel := Detach(parent, time.Minute)
+	defer cancel()
+
+	cancelParent()
+
+	if ctx.Err() != nil {
+		t.Error("expected detached context to outlive its parent")
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("expected detached context to have a deadline")
+	}
+	if id, _ := RequestIDFromContext(ctx); id != "req-1" {
+		t.Errorf("expected request ID %q, got %q", "req-1", id)
+	}
+	if tenant, _ := TenantFromContext(ctx); tenant != "acme" {
+		t.Errorf("expected tenant %q, got %q", "acme", tenant)
+	}
+	if ctx.Value(otherKey{}) != nil {
+		t.Error("expected unregistered values to be left out")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of the logger.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// registeredValuesHandler adds the registered values of the context to the records, like the logger package.
+type registeredValuesHandler struct {
+	slog.Handler
+}
+
+func (h registeredValuesHandler) Handle(ctx context.Context, r slog.Record) error {
+	for name, v := range All(ctx) {
+		r.AddAttrs(slog.Any(name, v))
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestGo_recoversPanics(t *testing.T) {
+	var buf syncBuffer
+	log := slog.New(registeredValuesHandler{Handler: slog.NewTextHandler(&buf, nil)})
+
+	ctx, cancel := Detach(WithRequestID(context.Background(), "req-1"), 0)
+	defer cancel()
+
+	done := make(chan struct{})
+	Go(ctx, log, func(ctx context.Context) {
+		defer close(done)
+		panic("boom")
+	})
+
+	<-done
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), "panic=boom") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the panic to be logged, got %q", buf.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "panic in background goroutine") {
+		t.Errorf("expected panic message in log, got %q", out)
+	}
+	if !strings.Contains(out, "request_id=req-1") {
+		t.Errorf("expected request ID in log, got %q", out)
+	}
+}
@@ -0,0 +1,120 @@
+package ctxval
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+)
+
+type (
+	// Key is a typed context key, values stored with a Key[T] are always of type T.
+	//
+	// Keys are compared by identity, so two keys with the same name never collide.
+	// Declare them once as package-level variables:
+	//
+	//	var userKey = ctxval.NewKey[*User]("user")
+	Key[T any] struct {
+		name string
+	}
+
+	// RegisteredKey is a key that can be added to the registry, see Register.
+	// It is implemented by *Key[T].
+	RegisteredKey interface {
+		// Name returns the name of the key, used as the attribute key in logs.
+		Name() string
+		value(ctx context.Context) (any, bool)
+		// copyValue copies the value of the key, if any, from src to dst.
+		copyValue(dst, src context.Context) context.Context
+	}
+)
+
+var registry struct {
+	mu   sync.RWMutex
+	keys []RegisteredKey
+}
+
+// NewKey creates a new key for values of type T.
+func NewKey[T any](name string) *Key[T] {
+	return &Key[T]{name: name}
+}
+
+// Name returns the name of the key.
+func (k *Key[T]) Name() string {
+	return k.name
+}
+
+// With returns a copy of ctx holding v.
+func (k *Key[T]) With(ctx context.Context, v T) context.Context {
+	return context.WithValue(ctx, k, v)
+}
+
+// From returns the value stored in ctx, if any.
+func (k *Key[T]) From(ctx context.Context) (T, bool) {
+	v, ok := ctx.Value(k).(T)
+	return v, ok
+}
+
+// MustFrom returns the value stored in ctx and panics if there is none.
+// Use it where a middleware guarantees the value is set.
+func (k *Key[T]) MustFrom(ctx context.Context) T {
+	v, ok := k.From(ctx)
+	if !ok {
+		panic(fmt.Sprintf("ctxval: no %s in context", k.name))
+	}
+
+	return v
+}
+
+func (k *Key[T]) value(ctx context.Context) (any, bool) {
+	return k.From(ctx)
+}
+
+func (k *Key[T]) copyValue(dst, src context.Context) context.Context {
+	v, ok := k.From(src)
+	if !ok {
+		return dst
+	}
+
+	return k.With(dst, v)
+}
+
+// Register adds keys to the registry. The values of the registered keys are
+// returned by All and copied by Detach, the logger adds them to every record as attributes
+// named after the keys.
+// Registering a key twice has no effect.
+func Register(keys ...RegisteredKey) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for _, k := range keys {
+		if !slices.Contains(registry.keys, k) {
+			registry.keys = append(registry.keys, k)
+		}
+	}
+}
+
+// registeredKeys returns a copy of the registered keys.
+func registeredKeys() []RegisteredKey {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	return slices.Clone(registry.keys)
+}
+
+// All returns the name and the value of every registered key set in ctx, in the order of registration.
+func All(ctx context.Context) iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for _, k := range registeredKeys() {
+			v, ok := k.value(ctx)
+			if !ok {
+				continue
+			}
+
+			if !yield(k.Name(), v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package ctxval
+
+import (
+	"context"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	userKey := NewKey[string]("user")
+	otherKey := NewKey[string]("user")
+
+	ctx := userKey.With(context.Background(), "alice")
+
+	val, ok := userKey.From(ctx)
+	if !ok || val != "alice" {
+		t.Errorf("expected %q, got %q (found: %v)", "alice", val, ok)
+	}
+
+	// Keys are compared by identity, not by name.
+	if _, ok := otherKey.From(ctx); ok {
+		t.Error("expected no value for another key with the same name")
+	}
+
+	if got := userKey.MustFrom(ctx); got != "alice" {
+		t.Errorf("expected %q, got %q", "alice", got)
+	}
+}
+
+func TestKey_MustFromPanics(t *testing.T) {
+	key := NewKey[int]("count")
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected MustFrom to panic without a value")
+		}
+	}()
+
+	key.MustFrom(context.Background())
+}
+
+// restoreRegistry restores the registered keys once the test is done.
+func restoreRegistry(t *testing.T) {
+	t.Helper()
+
+	keys := registeredKeys()
+
+	t.Cleanup(func() {
+		registry.mu.Lock()
+		defer registry.mu.Unlock()
+
+		registry.keys = keys
+	})
+}
+
+func TestAll(t *testing.T) {
+	restoreRegistry(t)
+
+	tenantKey := NewKey[string]("test_tenant")
+	unregisteredKey := NewKey[string]("unregistered")
+	Register(tenantKey, tenantKey)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = tenantKey.With(ctx, "acme")
+	ctx = unregisteredKey.With(ctx, "hidden")
+
+	got := make(map[string]any)
+	for name, v := range All(ctx) {
+		got[name] = v
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 values, got %v", got)
+	}
+	if got["request_id"] != "req-1" {
+		t.Errorf("expected request_id %q, got %v", "req-1", got["request_id"])
+	}
+	if got["test_tenant"] != "acme" {
+		t.Errorf("expected test_tenant %q, got %v", "acme", got["test_tenant"])
+	}
+}
@@ -0,0 +1,59 @@
+package ctxval
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// ID identifies the caller, e.g. the user ID or the client ID of a service.
+	ID string
+	// Roles are the roles granted to the caller.
+	Roles []string
+}
+
+var (
+	principalKey = NewKey[Principal]("principal")
+	tenantKey    = NewKey[string]("tenant_id")
+	localeKey    = NewKey[string]("locale")
+)
+
+func init() {
+	Register(principalKey, tenantKey, localeKey)
+}
+
+// LogValue logs only the ID of the principal.
+func (p Principal) LogValue() slog.Value {
+	return slog.StringValue(p.ID)
+}
+
+// WithPrincipal adds the authenticated caller to the given context.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return principalKey.With(ctx, p)
+}
+
+// PrincipalFromContext extracts the authenticated caller from the context, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	return principalKey.From(ctx)
+}
+
+// WithTenant adds a tenant ID to the given context.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return tenantKey.With(ctx, tenantID)
+}
+
+// TenantFromContext extracts the tenant ID from the context, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	return tenantKey.From(ctx)
+}
+
+// WithLocale adds a locale, a BCP 47 language tag like en-US, to the given context.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return localeKey.With(ctx, locale)
+}
+
+// LocaleFromContext extracts the locale from the context, if any.
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	return localeKey.From(ctx)
+}
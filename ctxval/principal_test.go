@@ -0,0 +1,35 @@
+package ctxval
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContextPrincipalTenantLocale(t *testing.T) {
+	ctx := context.Background()
+
+	if _, ok := PrincipalFromContext(ctx); ok {
+		t.Error("expected no principal in empty context")
+	}
+
+	ctx = WithPrincipal(ctx, Principal{ID: "user-1", Roles: []string{"admin"}})
+	ctx = WithTenant(ctx, "acme")
+	ctx = WithLocale(ctx, "en-US")
+
+	p, ok := PrincipalFromContext(ctx)
+	if !ok || p.ID != "user-1" {
+		t.Errorf("expected principal %q, got %+v", "user-1", p)
+	}
+
+	if tenant, _ := TenantFromContext(ctx); tenant != "acme" {
+		t.Errorf("expected tenant %q, got %q", "acme", tenant)
+	}
+
+	if locale, _ := LocaleFromContext(ctx); locale != "en-US" {
+		t.Errorf("expected locale %q, got %q", "en-US", locale)
+	}
+
+	if got := p.LogValue().String(); got != "user-1" {
+		t.Errorf("expected principal to log as %q, got %q", "user-1", got)
+	}
+}
@@ -2,17 +2,18 @@ package ctxval
 
 import "context"
 
-type contextKey string
+var requestIDKey = NewKey[string]("request_id")
 
-const requestIDKey contextKey = "request_id"
+func init() {
+	Register(requestIDKey)
+}
 
 // WithRequestID adds a request ID to the given context.
 func WithRequestID(ctx context.Context, requestID string) context.Context {
-	return context.WithValue(ctx, requestIDKey, requestID)
+	return requestIDKey.With(ctx, requestID)
 }
 
 // RequestIDFromContext extracts the request ID from the context, if any.
 func RequestIDFromContext(ctx context.Context) (string, bool) {
-	id, ok := ctx.Value(requestIDKey).(string)
-	return id, ok
+	return requestIDKey.From(ctx)
 }
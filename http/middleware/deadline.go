@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pushkar-anand/build-with-go/ctxval"
+	"github.com/pushkar-anand/build-with-go/http/response"
+)
+
+const (
+	// RequestTimeoutHeader holds the time budget of the caller, in seconds like 2.5 or as a duration like 2500ms.
+	RequestTimeoutHeader = "Request-Timeout"
+	// RequestDeadlineHeader holds the deadline of the caller as an RFC 3339 timestamp.
+	RequestDeadlineHeader = "X-Request-Deadline"
+)
+
+var deadlineExceededProblem = response.NewProblem().
+	WithStatus(http.StatusGatewayTimeout).
+	WithDetail("The request could not be completed within its deadline.").
+	Build()
+
+// deadlineWriter records whether the handler has started the response.
+type deadlineWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (dw *deadlineWriter) WriteHeader(status int) {
+	dw.wroteHeader = true
+	dw.ResponseWriter.WriteHeader(status)
+}
+
+func (dw *deadlineWriter) Write(b []byte) (int, error) {
+	dw.wroteHeader = true
+	return dw.ResponseWriter.Write(b)
+}
+
+func (dw *deadlineWriter) Unwrap() http.ResponseWriter {
+	return dw.ResponseWriter
+}
+
+// DeadlineBudget returns a middleware that bounds each request by the time budget of its caller.
+//
+// The budget is read from the Request-Timeout or the X-Request-Deadline header and capped by maxBudget,
+// requests without a valid header, or with a budget too large for a time.Duration, get maxBudget.
+// A maxBudget of 0 leaves the budget of the caller uncapped.
+// The budget is set as the deadline of the request context and stored with ctxval.WithTimeBudget.
+//
+// When the budget is already spent, or the handler returns after the deadline without writing
+// a response, a 504 Gateway Timeout problem is written with jw.
+func DeadlineBudget(maxBudget time.Duration, jw *response.JSONWriter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			budget, ok := requestBudget(r, time.Now())
+			if !ok || (maxBudget > 0 && budget > maxBudget) {
+				budget = maxBudget
+			}
+
+			if budget == 0 && !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if budget <= 0 {
+				jw.WriteProblem(r.Context(), r, w, deadlineExceededProblem)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), budget)
+			defer cancel()
+
+			ctx = ctxval.WithTimeBudget(ctx, budget)
+
+			dw := &deadlineWriter{ResponseWriter: w}
+			next.ServeHTTP(dw, r.WithContext(ctx))
+
+			if !dw.wroteHeader && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				jw.WriteProblem(ctx, r, w, deadlineExceededProblem)
+			}
+		})
+	}
+}
+
+// maxBudgetSeconds is the largest budget in seconds that fits in a time.Duration.
+const maxBudgetSeconds = float64(math.MaxInt64 / int64(time.Second))
+
+// requestBudget returns the time budget sent by the caller, it may be negative when
+// the deadline has already passed. A budget too large for a time.Duration is treated as no budget.
+func requestBudget(r *http.Request, now time.Time) (time.Duration, bool) {
+	if v := strings.TrimSpace(r.Header.Get(RequestTimeoutHeader)); v != "" {
+		if seconds, err := strconv.ParseFloat(v, 64); err == nil && !math.IsInf(seconds, 0) && !math.IsNaN(seconds) {
+			if seconds > maxBudgetSeconds {
+				return 0, false
+			}
+
+			return time.Duration(max(seconds, -maxBudgetSeconds) * float64(time.Second)), true
+		}
+
+		if d, err := time.ParseDuration(v); err == nil {
+			return d, true
+		}
+	}
+
+	if v := strings.TrimSpace(r.Header.Get(RequestDeadlineHeader)); v != "" {
+		if deadline, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return deadline.Sub(now), true
+		}
+	}
+
+	return 0, false
+}
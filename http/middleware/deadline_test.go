@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pushkar-anand/build-with-go/ctxval"
+	"github.com/pushkar-anand/build-with-go/http/response"
+)
+
+func TestDeadlineBudget(t *testing.T) {
+	jw := response.NewJSONWriter(slog.Default())
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		max      time.Duration
+		expected time.Duration
+	}{
+		{name: "seconds", header: RequestTimeoutHeader, value: "2.5", max: time.Minute, expected: 2500 * time.Millisecond},
+		{name: "duration", header: RequestTimeoutHeader, value: "300ms", max: time.Minute, expected: 300 * time.Millisecond},
+		{name: "deadline", header: RequestDeadlineHeader, value: now.Add(10 * time.Second).Format(time.RFC3339Nano), max: time.Minute, expected: 10 * time.Second},
+		{name: "capped by max", header: RequestTimeoutHeader, value: "120", max: time.Minute, expected: time.Minute},
+		{name: "too large seconds", header: RequestTimeoutHeader, value: "9999999999999", max: time.Minute, expected: time.Minute},
+		{name: "too large exponent", header: RequestTimeoutHeader, value: "1e20", max: time.Minute, expected: time.Minute},
+		{name: "invalid header", header: RequestTimeoutHeader, value: "soon", max: time.Minute, expected: time.Minute},
+		{name: "no header", max: 5 * time.Second, expected: 5 * time.Second},
+		{name: "uncapped", header: RequestTimeoutHeader, value: "120", expected: 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				budget      time.Duration
+				hasDeadline bool
+			)
+			handler := DeadlineBudget(tt.max, jw)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				budget, _ = ctxval.TimeBudgetFromContext(r.Context())
+				_, hasDeadline = r.Context().Deadline()
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected status OK, got %v", rr.Code)
+			}
+			if !hasDeadline {
+				t.Error("expected the request context to have a deadline")
+			}
+			// The deadline header is relative to the time of the request.
+			if diff := tt.expected - budget; diff < 0 || diff > time.Second {
+				t.Errorf("expected budget %v, got %v", tt.expected, budget)
+			}
+		})
+	}
+}
+
+func TestDeadlineBudget_noBudget(t *testing.T) {
+	handler := DeadlineBudget(0, response.NewJSONWriter(slog.Default()))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); ok {
+			t.Error("expected no deadline without a header and a max")
+		}
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
+func TestDeadlineBudget_exceeded(t *testing.T) {
+	jw := response.NewJSONWriter(slog.Default())
+
+	t.Run("budget already spent", func(t *testing.T) {
+		called := false
+		handler := DeadlineBudget(time.Minute, jw)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestDeadlineHeader, time.Now().Add(-time.Second).Format(time.RFC3339Nano))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if called {
+			t.Error("expected the handler not to be called")
+		}
+		assertGatewayTimeout(t, rr)
+	})
+
+	t.Run("handler runs past the deadline", func(t *testing.T) {
+		handler := DeadlineBudget(time.Minute, jw)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestTimeoutHeader, "10ms")
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assertGatewayTimeout(t, rr)
+	})
+}
+
+func assertGatewayTimeout(t *testing.T, rr *httptest.ResponseRecorder) {
+	t.Helper()
+
+	if rr.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected status %v, got %v", http.StatusGatewayTimeout, rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json; charset=utf-8" {
+		t.Errorf("expected problem+json content type, got %q", ct)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("expected JSON body: %v", err)
+	}
+	if body["status"] != float64(http.StatusGatewayTimeout) {
+		t.Errorf("expected status 504 in body, got %v", body["status"])
+	}
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pushkar-anand/build-with-go/ctxval"
+)
+
+// languageRange is a language of the Accept-Language header with its weight.
+type languageRange struct {
+	tag    string
+	weight float64
+}
+
+// Locale returns a middleware that adds the locale of each request to its context.
+//
+// The locale is the supported locale, e.g. en-US, that best matches the Accept-Language header.
+// A language matches a locale exactly or by its base language: en matches en-US and en-GB matches en.
+// Without a match, the first supported locale is used.
+func Locale(supported ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Language")
+
+			if locale, ok := matchLocale(r.Header.Get("Accept-Language"), supported); ok {
+				r = r.WithContext(ctxval.WithLocale(r.Context(), locale))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// matchLocale returns the supported locale that best matches the Accept-Language header,
+// or the first supported locale when none matches.
+func matchLocale(header string, supported []string) (string, bool) {
+	if len(supported) == 0 {
+		return "", false
+	}
+
+	for _, lr := range parseAcceptLanguage(header) {
+		if lr.tag == "*" {
+			return supported[0], true
+		}
+
+		if locale, ok := findLocale(lr.tag, supported); ok {
+			return locale, true
+		}
+	}
+
+	return supported[0], true
+}
+
+// findLocale returns the supported locale matching tag exactly, then by base language.
+func findLocale(tag string, supported []string) (string, bool) {
+	for _, locale := range supported {
+		if strings.EqualFold(locale, tag) {
+			return locale, true
+		}
+	}
+
+	base, _, _ := strings.Cut(tag, "-")
+	for _, locale := range supported {
+		localeBase, _, _ := strings.Cut(locale, "-")
+		if strings.EqualFold(localeBase, base) {
+			return locale, true
+		}
+	}
+
+	return "", false
+}
+
+// parseAcceptLanguage returns the languages of an Accept-Language header, from the most to
+// the least preferred. Languages with a weight of 0 are left out.
+func parseAcceptLanguage(header string) []languageRange {
+	var ranges []languageRange
+
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(strings.ReplaceAll(tag, "_", "-"))
+		if tag == "" {
+			continue
+		}
+
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			w, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+
+			weight = w
+		}
+
+		if weight <= 0 {
+			continue
+		}
+
+		ranges = append(ranges, languageRange{tag: tag, weight: weight})
+	}
+
+	// The order of the header breaks ties.
+	slices.SortStableFunc(ranges, func(a, b languageRange) int {
+		return cmp.Compare(b.weight, a.weight)
+	})
+
+	return ranges
+}
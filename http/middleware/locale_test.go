@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pushkar-anand/build-with-go/ctxval"
+)
+
+func TestLocale(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "no header", header: "", expected: "en-US"},
+		{name: "exact match", header: "fr-FR", expected: "fr-FR"},
+		{name: "case insensitive", header: "fr-fr", expected: "fr-FR"},
+		{name: "base language", header: "fr", expected: "fr-FR"},
+		{name: "region falls back to base language", header: "de-AT", expected: "de"},
+		{name: "weights", header: "de;q=0.5, fr-CA;q=0.8, ja", expected: "fr-FR"},
+		{name: "ties keep header order", header: "de, fr", expected: "de"},
+		{name: "zero weight is excluded", header: "fr;q=0, de;q=0.1", expected: "de"},
+		{name: "unsupported", header: "ja, zh", expected: "en-US"},
+	}
+
+	mw := Locale("en-US", "fr-FR", "de")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var locale string
+			handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				locale, _ = ctxval.LocaleFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Accept-Language", tt.header)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if locale != tt.expected {
+				t.Errorf("expected locale %q, got %q", tt.expected, locale)
+			}
+			if rr.Header().Get("Vary") != "Accept-Language" {
+				t.Errorf("expected Vary: Accept-Language, got %q", rr.Header().Get("Vary"))
+			}
+		})
+	}
+}
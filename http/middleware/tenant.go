@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/pushkar-anand/build-with-go/ctxval"
+)
+
+// TenantResolver extracts the tenant ID from a request, it returns false when the request has none.
+type TenantResolver func(r *http.Request) (string, bool)
+
+// Tenant returns a middleware that adds the tenant ID of each request to its context.
+// The resolvers are tried in order and the first tenant found is used.
+// When none of them finds a tenant, the request is passed on without one.
+func Tenant(resolvers ...TenantResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, resolve := range resolvers {
+				if tenant, ok := resolve(r); ok {
+					r = r.WithContext(ctxval.WithTenant(r.Context(), tenant))
+					break
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TenantFromHeader resolves the tenant from the request header name, e.g. X-Tenant-Id.
+func TenantFromHeader(name string) TenantResolver {
+	return func(r *http.Request) (string, bool) {
+		tenant := strings.TrimSpace(r.Header.Get(name))
+		return tenant, tenant != ""
+	}
+}
+
+// TenantFromSubdomain resolves the tenant from the subdomain of baseDomain in the Host header:
+// with the base domain example.com, acme.example.com is the tenant acme.
+func TenantFromSubdomain(baseDomain string) TenantResolver {
+	suffix := "." + strings.ToLower(strings.Trim(baseDomain, "."))
+
+	return func(r *http.Request) (string, bool) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		sub, ok := strings.CutSuffix(strings.ToLower(host), suffix)
+		if !ok || sub == "" || strings.Contains(sub, ".") {
+			return "", false
+		}
+
+		return sub, true
+	}
+}
+
+// TenantFromPath resolves the tenant from the path segment following prefix:
+// with the prefix /tenants, /tenants/acme/orders is the tenant acme.
+// With an empty prefix, the tenant is the first segment of the path.
+func TenantFromPath(prefix string) TenantResolver {
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix = "/" + prefix
+	}
+	prefix += "/"
+
+	return func(r *http.Request) (string, bool) {
+		rest, ok := strings.CutPrefix(r.URL.Path, prefix)
+		if !ok {
+			return "", false
+		}
+
+		tenant, _, _ := strings.Cut(rest, "/")
+		return tenant, tenant != ""
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pushkar-anand/build-with-go/ctxval"
+)
+
+func TestTenant(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		host     string
+		header   string
+		expected string
+	}{
+		{name: "header", target: "/orders", header: "acme", expected: "acme"},
+		{name: "subdomain", target: "/orders", host: "acme.example.com:8080", expected: "acme"},
+		{name: "path", target: "/tenants/acme/orders", expected: "acme"},
+		{name: "header wins", target: "/tenants/other/orders", header: "acme", expected: "acme"},
+		{name: "nested subdomain is ignored", target: "/orders", host: "a.acme.example.com"},
+		{name: "no tenant", target: "/orders", host: "example.com"},
+	}
+
+	mw := Tenant(
+		TenantFromHeader("X-Tenant-Id"),
+		TenantFromSubdomain("example.com"),
+		TenantFromPath("/tenants"),
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				tenant string
+				found  bool
+			)
+			handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tenant, found = ctxval.TenantFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", tt.target, nil)
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.header != "" {
+				req.Header.Set("X-Tenant-Id", tt.header)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if found != (tt.expected != "") {
+				t.Fatalf("expected tenant found to be %v, got %v", tt.expected != "", found)
+			}
+			if tenant != tt.expected {
+				t.Errorf("expected tenant %q, got %q", tt.expected, tenant)
+			}
+		})
+	}
+}
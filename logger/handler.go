@@ -8,24 +8,32 @@ import (
 )
 
 // contextHandler is a slog.Handler that adds context values to log records.
+// Every key registered with ctxval.Register is added as an attribute named after the key,
+// followed by the attributes of the extractors.
 type contextHandler struct {
 	slog.Handler
+	extractors []ContextExtractor
 }
 
 // Handle adds context values to the log record before passing it to the underlying handler.
 func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
-	if reqID, ok := ctxval.RequestIDFromContext(ctx); ok {
-		r.AddAttrs(slog.String("request_id", reqID))
+	for name, v := range ctxval.All(ctx) {
+		r.AddAttrs(slog.Any(name, v))
 	}
+
+	for _, extract := range h.extractors {
+		r.AddAttrs(extract(ctx)...)
+	}
+
 	return h.Handler.Handle(ctx, r)
 }
 
 // WithAttrs returns a new handler with the given attributes, retaining the context wrapper.
 func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &contextHandler{h.Handler.WithAttrs(attrs)}
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs), extractors: h.extractors}
 }
 
 // WithGroup returns a new handler with the given group, retaining the context wrapper.
 func (h *contextHandler) WithGroup(name string) slog.Handler {
-	return &contextHandler{h.Handler.WithGroup(name)}
+	return &contextHandler{Handler: h.Handler.WithGroup(name), extractors: h.extractors}
 }
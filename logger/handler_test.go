@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/pushkar-anand/build-with-go/ctxval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextHandler_registeredKeys(t *testing.T) {
+	// The registry is global, the test uses keys registered by ctxval.
+	var buf bytes.Buffer
+	log := New(WithWriter(&buf), WithFormat(FormatJSON))
+
+	ctx := ctxval.WithRequestID(context.Background(), "req-1")
+	ctx = ctxval.WithTenant(ctx, "acme")
+
+	log.InfoContext(ctx, "order placed")
+
+	var entry map[string]any
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	require.NoError(t, err)
+
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "acme", entry["tenant_id"])
+}
+
+func TestContextHandler_principalTenantLocale(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(WithWriter(&buf), WithFormat(FormatJSON))
+
+	ctx := ctxval.WithPrincipal(context.Background(), ctxval.Principal{ID: "user-1", Roles: []string{"admin"}})
+	ctx = ctxval.WithTenant(ctx, "acme")
+	ctx = ctxval.WithLocale(ctx, "en-US")
+
+	log.InfoContext(ctx, "hello")
+
+	var entry map[string]any
+	err := json.Unmarshal(buf.Bytes(), &entry)
+	require.NoError(t, err)
+
+	assert.Equal(t, "user-1", entry["principal"])
+	assert.Equal(t, "acme", entry["tenant_id"])
+	assert.Equal(t, "en-US", entry["locale"])
+}
+
+func TestContextHandler_extractors(t *testing.T) {
+	type traceKey struct{}
+
+	var buf bytes.Buffer
+	log := New(
+		WithWriter(&buf),
+		WithFormat(FormatText),
+		WithContextExtractor(func(ctx context.Context) []slog.Attr {
+			if id, ok := ctx.Value(traceKey{}).(string); ok {
+				return []slog.Attr{slog.String("trace_id", id)}
+			}
+			return nil
+		}),
+		WithContextExtractor(func(ctx context.Context) []slog.Attr {
+			return []slog.Attr{slog.String("variant", "b")}
+		}),
+	)
+
+	ctx := ctxval.WithRequestID(context.Background(), "req-1")
+	ctx = context.WithValue(ctx, traceKey{}, "trace-1")
+
+	log.With("service", "orders").InfoContext(ctx, "hello")
+
+	assert.Contains(t, buf.String(), "service=orders request_id=req-1 trace_id=trace-1 variant=b")
+}
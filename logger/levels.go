@@ -0,0 +1,215 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NameKey is the attribute key naming a logger, see Named.
+const NameKey = "logger"
+
+type (
+	// Levels holds the minimum level of a logger and of its named loggers, it can be changed at runtime.
+	//
+	// The level of a named logger is its override, or the override of its closest parent
+	// when it has none: http.client falls back to http. Loggers without an override use the
+	// default level, kept in a slog.LevelVar.
+	Levels struct {
+		level *slog.LevelVar
+
+		mu        sync.RWMutex
+		overrides map[string]slog.Level
+		reverts   map[string]*levelRevert
+	}
+
+	// levelRevert restores a level when its TTL expires.
+	levelRevert struct {
+		timer     *time.Timer
+		expiresAt time.Time
+		// previous is the level to restore, nil when there was no override.
+		previous *slog.Level
+	}
+
+	// levelHandler filters records by the level of the named logger that logs them.
+	levelHandler struct {
+		slog.Handler
+		levels *Levels
+		name   string
+	}
+)
+
+// minLevel lets every record through the handlers wrapped by levelHandler.
+const minLevel = slog.Level(math.MinInt)
+
+// NewLevels creates Levels with the given default level.
+func NewLevels(level slog.Level) *Levels {
+	l := &Levels{
+		level:     new(slog.LevelVar),
+		overrides: make(map[string]slog.Level),
+		reverts:   make(map[string]*levelRevert),
+	}
+	l.level.Set(level)
+
+	return l
+}
+
+// Named returns a logger named name, its level can be overridden in Levels.
+func Named(log *slog.Logger, name string) *slog.Logger {
+	return log.With(slog.String(NameKey, name))
+}
+
+// LevelVar returns the variable holding the default level.
+func (l *Levels) LevelVar() *slog.LevelVar {
+	return l.level
+}
+
+// Level returns the level of the logger name, an empty name is the default level.
+func (l *Levels) Level(name string) slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for name != "" {
+		if level, ok := l.overrides[name]; ok {
+			return level
+		}
+
+		i := strings.LastIndex(name, ".")
+		if i < 0 {
+			break
+		}
+
+		name = name[:i]
+	}
+
+	return l.level.Level()
+}
+
+// Set sets the level of the logger name, an empty name sets the default level.
+// With a positive ttl, the previous level is restored once it expires.
+func (l *Levels) Set(name string, level slog.Level, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// A pending revert keeps the level from before the first temporary change.
+	previous := l.current(name)
+	if r, ok := l.reverts[name]; ok {
+		r.timer.Stop()
+		previous = r.previous
+		delete(l.reverts, name)
+	}
+
+	l.set(name, &level)
+
+	if ttl <= 0 {
+		return
+	}
+
+	r := &levelRevert{expiresAt: time.Now().Add(ttl), previous: previous}
+	r.timer = time.AfterFunc(ttl, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		// The revert may have been replaced while the timer fired.
+		if l.reverts[name] != r {
+			return
+		}
+
+		delete(l.reverts, name)
+		l.set(name, r.previous)
+	})
+	l.reverts[name] = r
+}
+
+// Reset removes the override of the logger name, it uses the level of its parent again.
+func (l *Levels) Reset(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if r, ok := l.reverts[name]; ok {
+		r.timer.Stop()
+		delete(l.reverts, name)
+	}
+
+	delete(l.overrides, name)
+}
+
+// ParseOverrides sets the levels of named loggers from a list like http=warn,db=debug.
+// A name without a level, like info, sets the default level.
+func (l *Levels) ParseOverrides(s string) error {
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(item, "=")
+		if !found {
+			name, value = "", name
+		}
+
+		var level slog.Level
+		err := level.UnmarshalText([]byte(strings.TrimSpace(value)))
+		if err != nil {
+			return fmt.Errorf("invalid level override %q: %w", item, err)
+		}
+
+		l.Set(strings.TrimSpace(name), level, 0)
+	}
+
+	return nil
+}
+
+// current returns the own level of name, nil for a named logger without an override.
+// It must be called with the lock held.
+func (l *Levels) current(name string) *slog.Level {
+	if name == "" {
+		level := l.level.Level()
+		return &level
+	}
+
+	level, ok := l.overrides[name]
+	if !ok {
+		return nil
+	}
+
+	return &level
+}
+
+// set sets the own level of name, nil removes the override. It must be called with the lock held.
+func (l *Levels) set(name string, level *slog.Level) {
+	switch {
+	case name == "":
+		l.level.Set(*level)
+	case level == nil:
+		delete(l.overrides, name)
+	default:
+		l.overrides[name] = *level
+	}
+}
+
+// Enabled reports whether the named logger logs at level.
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.levels.Level(h.name)
+}
+
+// WithAttrs returns a new handler with the given attributes, an attribute with NameKey names the logger.
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	name := h.name
+	for _, a := range attrs {
+		if a.Key == NameKey {
+			name = a.Value.String()
+		}
+	}
+
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), levels: h.levels, name: name}
+}
+
+// WithGroup returns a new handler with the given group, keeping the name of the logger.
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), levels: h.levels, name: h.name}
+}
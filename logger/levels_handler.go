@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type (
+	// levelsState is the JSON body returned by the levels handler.
+	levelsState struct {
+		Level     string                   `json:"level"`
+		ExpiresAt *time.Time               `json:"expires_at,omitempty"`
+		Overrides map[string]levelOverride `json:"overrides"`
+	}
+
+	levelOverride struct {
+		Level     string     `json:"level"`
+		ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	}
+
+	// levelChange is the JSON body accepted by the levels handler.
+	levelChange struct {
+		// Name is the name of the logger, empty for the default level.
+		Name string `json:"name"`
+		// Level is the new level, empty to remove the override of a named logger.
+		Level string `json:"level"`
+		// TTL is how long the level is kept before the previous one is restored, e.g. 15m.
+		TTL string `json:"ttl"`
+	}
+
+	levelsHandler struct {
+		levels *Levels
+	}
+)
+
+// Handler returns an admin http.Handler to read and change the levels at runtime.
+//
+// GET returns the default level and the overrides. PUT changes a level with a body like
+// {"name": "db", "level": "debug", "ttl": "15m"} and returns the new levels: an empty name
+// changes the default level, an empty level removes the override and the optional ttl
+// restores the previous level once it expires.
+//
+// The handler must only be reachable by operators, it has no authentication of its own.
+func (l *Levels) Handler() http.Handler {
+	return &levelsHandler{levels: l}
+}
+
+func (h *levelsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.writeState(w, http.StatusOK)
+	case http.MethodPut:
+		h.change(w, r)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		writeJSONError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (h *levelsHandler) change(w http.ResponseWriter, r *http.Request) {
+	var change levelChange
+
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&change)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid body: "+err.Error())
+		return
+	}
+
+	var ttl time.Duration
+	if change.TTL != "" {
+		ttl, err = time.ParseDuration(change.TTL)
+		if err != nil || ttl < 0 {
+			writeJSONError(w, http.StatusBadRequest, "invalid ttl: "+change.TTL)
+			return
+		}
+	}
+
+	if change.Level == "" {
+		if change.Name == "" {
+			writeJSONError(w, http.StatusBadRequest, "level is required for the default level")
+			return
+		}
+
+		h.levels.Reset(change.Name)
+		h.writeState(w, http.StatusOK)
+		return
+	}
+
+	var level slog.Level
+	err = level.UnmarshalText([]byte(change.Level))
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid level: "+change.Level)
+		return
+	}
+
+	h.levels.Set(change.Name, level, ttl)
+	h.writeState(w, http.StatusOK)
+}
+
+func (h *levelsHandler) writeState(w http.ResponseWriter, status int) {
+	l := h.levels
+
+	l.mu.RLock()
+	state := levelsState{
+		Level:     l.level.Level().String(),
+		Overrides: make(map[string]levelOverride, len(l.overrides)),
+	}
+
+	if r, ok := l.reverts[""]; ok {
+		state.ExpiresAt = &r.expiresAt
+	}
+
+	for name, level := range l.overrides {
+		o := levelOverride{Level: level.String()}
+		if r, ok := l.reverts[name]; ok {
+			o.ExpiresAt = &r.expiresAt
+		}
+
+		state.Overrides[name] = o
+	}
+	l.mu.RUnlock()
+
+	writeJSON(w, status, state)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
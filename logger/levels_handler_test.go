@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevels_Handler(t *testing.T) {
+	levels := NewLevels(slog.LevelInfo)
+	handler := levels.Handler()
+
+	do := func(method, body string) (*httptest.ResponseRecorder, levelsState) {
+		req := httptest.NewRequest(method, "/admin/log-levels", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		var state levelsState
+		if rr.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &state))
+		}
+
+		return rr, state
+	}
+
+	rr, state := do(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "INFO", state.Level)
+	assert.Empty(t, state.Overrides)
+
+	rr, state = do(http.MethodPut, `{"name": "db", "level": "debug", "ttl": "15m"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "DEBUG", state.Overrides["db"].Level)
+	assert.NotNil(t, state.Overrides["db"].ExpiresAt)
+	assert.Equal(t, slog.LevelDebug, levels.Level("db"))
+
+	rr, state = do(http.MethodPut, `{"level": "warn"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "WARN", state.Level)
+	assert.Nil(t, state.ExpiresAt)
+
+	rr, state = do(http.MethodPut, `{"name": "db"}`)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, state.Overrides)
+
+	rr, _ = do(http.MethodPut, `{"name": "db", "level": "loud"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr, _ = do(http.MethodPut, `{"name": "db", "level": "debug", "ttl": "soon"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr, _ = do(http.MethodDelete, "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+	assert.Equal(t, "GET, PUT", rr.Header().Get("Allow"))
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevels_namedLoggers(t *testing.T) {
+	levels := NewLevels(slog.LevelInfo)
+	require.NoError(t, levels.ParseOverrides("http=warn, db=debug"))
+
+	var buf bytes.Buffer
+	log := New(WithWriter(&buf), WithLevels(levels))
+
+	log.Debug("root debug")
+	log.Info("root info")
+	Named(log, "http").Info("http info")
+	Named(log, "http").Warn("http warn")
+	Named(log, "http.client").Info("http client info")
+	Named(log, "db").Debug("db debug")
+	Named(log, "db").WithGroup("query").Debug("db group debug")
+
+	out := buf.String()
+	assert.NotContains(t, out, "root debug")
+	assert.Contains(t, out, "root info")
+	assert.NotContains(t, out, "http info")
+	assert.Contains(t, out, "http warn")
+	assert.NotContains(t, out, "http client info")
+	assert.Contains(t, out, "db debug")
+	assert.Contains(t, out, "db group debug")
+
+	buf.Reset()
+	levels.LevelVar().Set(slog.LevelDebug)
+	log.Debug("root debug")
+	assert.Contains(t, buf.String(), "root debug")
+}
+
+func TestLevels_ttl(t *testing.T) {
+	levels := NewLevels(slog.LevelInfo)
+	levels.Set("db", slog.LevelWarn, 0)
+
+	levels.Set("db", slog.LevelDebug, 20*time.Millisecond)
+	levels.Set("db", slog.LevelError, 20*time.Millisecond)
+	assert.Equal(t, slog.LevelError, levels.Level("db"))
+
+	levels.Set("", slog.LevelDebug, 20*time.Millisecond)
+	assert.Equal(t, slog.LevelDebug, levels.Level(""))
+
+	// The level from before the first temporary change is restored.
+	require.Eventually(t, func() bool {
+		return levels.Level("db") == slog.LevelWarn && levels.Level("") == slog.LevelInfo
+	}, 5*time.Second, 5*time.Millisecond)
+
+	levels.Set("cache", slog.LevelDebug, 20*time.Millisecond)
+	require.Eventually(t, func() bool {
+		return levels.Level("cache") == slog.LevelInfo
+	}, 5*time.Second, 5*time.Millisecond)
+}
+
+func TestLevels_ParseOverrides(t *testing.T) {
+	levels := NewLevels(slog.LevelInfo)
+
+	require.NoError(t, levels.ParseOverrides("warn,db=debug"))
+	assert.Equal(t, slog.LevelWarn, levels.Level(""))
+	assert.Equal(t, slog.LevelDebug, levels.Level("db"))
+
+	assert.EqualError(t, levels.ParseOverrides("db=loud"), `invalid level override "db=loud": slog: level string "loud": unknown name`)
+}
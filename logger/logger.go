@@ -18,22 +18,53 @@ func New(options ...Option) *slog.Logger {
 		option.apply(c)
 	}
 
+	levels := c.levels
+	if levels == nil {
+		levels = NewLevels(c.level)
+	}
+
+	var h slog.Handler
+
+	if len(c.sinks) == 0 {
+		// The levels are checked by levelHandler, which knows the name of the logger.
+		h = newHandler(c, minLevel)
+	} else {
+		handlers := make([]slog.Handler, 0, len(c.sinks))
+		for _, opts := range c.sinks {
+			sc := c.sinkConfig(opts)
+			handlers = append(handlers, newHandler(sc, sc.level))
+		}
+
+		h = &multiHandler{handlers: handlers}
+	}
+
+	if c.sampling != nil {
+		h = newSamplingHandler(h, c.sampling.first, c.sampling.thereafter, c.sampling.interval)
+	}
+
+	h = &levelHandler{Handler: h, levels: levels}
+
+	return slog.New(&contextHandler{Handler: h, extractors: c.extractors})
+}
+
+// newHandler returns the handler writing records at level or above to the writer of c, in its format.
+func newHandler(c *config, level slog.Leveler) slog.Handler {
 	opts := &slog.HandlerOptions{
 		AddSource:   c.addCaller,
-		Level:       c.level,
+		Level:       level,
 		ReplaceAttr: nil,
 	}
 
-	var h slog.Handler
+	if c.redactor.enabled() {
+		opts.ReplaceAttr = c.redactor.replaceAttr
+	}
 
 	switch c.format {
 	case FormatJSON:
-		h = slog.NewJSONHandler(c.writer, opts)
+		return slog.NewJSONHandler(c.writer, opts)
 	case FormatText:
-		h = slog.NewTextHandler(c.writer, opts)
+		return slog.NewTextHandler(c.writer, opts)
 	default:
-		h = slog.NewJSONHandler(c.writer, opts)
+		return slog.NewJSONHandler(c.writer, opts)
 	}
-
-	return slog.New(&contextHandler{h})
 }
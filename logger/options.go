@@ -1,19 +1,37 @@
 package logger
 
 import (
+	"context"
 	"io"
 	"log/slog"
 	"os"
+	"regexp"
+	"time"
 )
 
 type (
 	config struct {
-		level     slog.Level
-		addCaller bool
-		writer    io.Writer
-		format    Format
+		level      slog.Level
+		addCaller  bool
+		writer     io.Writer
+		format     Format
+		extractors []ContextExtractor
+		redactor   redactor
+		levels     *Levels
+		sampling   *samplingConfig
+		sinks      [][]Option
 	}
 
+	samplingConfig struct {
+		first      int
+		thereafter int
+		interval   time.Duration
+	}
+
+	// ContextExtractor returns the attributes to add to a log record from its context,
+	// like a trace ID or a feature-flag variant.
+	ContextExtractor func(ctx context.Context) []slog.Attr
+
 	Option interface {
 		apply(*config)
 	}
@@ -44,12 +62,103 @@ func WithLevel(l slog.Level) Option {
 	})
 }
 
+// WithLevels makes the logger use levels, which can be changed at runtime, instead of a fixed level.
+// The level set with WithLevel is ignored.
+func WithLevels(levels *Levels) Option {
+	return optionFunc(func(c *config) {
+		c.levels = levels
+	})
+}
+
+// WithSampling limits the records with the same level and message to the first ones of every
+// interval, then one in thereafter. With thereafter at 0, the records after the first ones are dropped.
+//
+// The number of suppressed records of each level and message is logged at the end of the interval,
+// even when no record follows. Records logged with a context flagged by ctxval.WithDebug
+// are never sampled.
+func WithSampling(first, thereafter int, interval time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.sampling = &samplingConfig{first: first, thereafter: thereafter, interval: interval}
+	})
+}
+
+// WithSink adds a sink, a writer with its own format, level and redaction, configured with
+// WithWriter, WithFormat, WithLevel, WithAddCaller and the redaction options.
+// Once a sink is added, records are written to the sinks only, not to the writer of the logger.
+//
+// The level of a sink is a minimum on top of the level of the logger, every level the logger
+// allows by default. A sink also redacts what the logger is configured to redact.
+// The other options, like WithLevels or WithSampling, apply to the logger as a whole.
+//
+//	logger.New(
+//		logger.WithSink(logger.WithWriter(os.Stdout), logger.WithFormat(logger.FormatJSON)),
+//		logger.WithSink(logger.WithWriter(file), logger.WithLevel(slog.LevelWarn)),
+//	)
+func WithSink(opts ...Option) Option {
+	return optionFunc(func(c *config) {
+		c.sinks = append(c.sinks, opts)
+	})
+}
+
 func WithAddCaller() Option {
 	return optionFunc(func(c *config) {
 		c.addCaller = true
 	})
 }
 
+// WithContextExtractor adds the attributes returned by fn to every record logged with a context.
+// Extractors run in the order they are added, after the values registered with ctxval.Register.
+func WithContextExtractor(fn ContextExtractor) Option {
+	return optionFunc(func(c *config) {
+		c.extractors = append(c.extractors, fn)
+	})
+}
+
+// WithRedactKeys redacts the values of the attributes with the given keys, compared case-insensitively.
+// The attributes nested in a group with one of the keys are redacted too, as are the entries
+// of logged maps and structs, e.g. the Authorization value of an http.Header.
+func WithRedactKeys(keys ...string) Option {
+	return optionFunc(func(c *config) {
+		c.redactor.keys = append(c.redactor.keys, keys...)
+	})
+}
+
+// WithRedactKeyPattern redacts the values of the attributes with a key matching pattern,
+// e.g. regexp.MustCompile(`(?i)(token|secret)$`).
+func WithRedactKeyPattern(pattern *regexp.Regexp) Option {
+	return optionFunc(func(c *config) {
+		c.redactor.keyPatterns = append(c.redactor.keyPatterns, pattern)
+	})
+}
+
+// WithRedactValuePattern redacts the parts of string values matching pattern, in every attribute
+// and the message, e.g. JWTPattern or EmailPattern. Errors and fmt.Stringer values are formatted
+// to be matched.
+func WithRedactValuePattern(pattern *regexp.Regexp) Option {
+	return optionFunc(func(c *config) {
+		c.redactor.valuePatterns = append(c.redactor.valuePatterns, pattern)
+	})
+}
+
+// WithRedactCardNumbers redacts the payment card numbers in string values and the message.
+// Only the matches of CardNumberPattern passing the Luhn check are redacted, so timestamps
+// and IDs of the same length are kept.
+func WithRedactCardNumbers() Option {
+	return optionFunc(func(c *config) {
+		c.redactor.cardNumbers = true
+	})
+}
+
+// WithDefaultRedaction redacts the DefaultRedactKeys, the values matching JWTPattern
+// and EmailPattern, and the card numbers as WithRedactCardNumbers does.
+func WithDefaultRedaction() Option {
+	return optionFunc(func(c *config) {
+		c.redactor.keys = append(c.redactor.keys, DefaultRedactKeys...)
+		c.redactor.valuePatterns = append(c.redactor.valuePatterns, JWTPattern, EmailPattern)
+		c.redactor.cardNumbers = true
+	})
+}
+
 func defaultConfig() *config {
 	return &config{
 		level:     slog.LevelDebug,
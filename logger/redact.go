@@ -0,0 +1,265 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	redactedValue = "[REDACTED]"
+	// maxRedactDepth bounds the walk of nested values, e.g. of cyclic pointers.
+	maxRedactDepth = 8
+)
+
+var (
+	// JWTPattern matches JSON Web Tokens.
+	JWTPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	// EmailPattern matches email addresses.
+	EmailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// CardNumberPattern matches payment card numbers (PANs) of 13 to 19 digits,
+	// optionally grouped with spaces or dashes. Used with WithRedactValuePattern, it redacts
+	// every match; WithRedactCardNumbers redacts only the numbers passing the Luhn check.
+	CardNumberPattern = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+
+	// DefaultRedactKeys are the attribute keys redacted by WithDefaultRedaction.
+	DefaultRedactKeys = []string{
+		"password", "passwd", "secret", "token", "access_token", "refresh_token",
+		"api_key", "apikey", "authorization", "cookie", "set-cookie",
+	}
+)
+
+type (
+	// Redacted is a string that is never logged, its value is replaced by [REDACTED].
+	//
+	//	log.Info("login", "password", logger.Redacted(password))
+	Redacted string
+
+	// redactor replaces sensitive attribute values with [REDACTED].
+	redactor struct {
+		keys          []string
+		keyPatterns   []*regexp.Regexp
+		valuePatterns []*regexp.Regexp
+		cardNumbers   bool
+	}
+)
+
+// LogValue hides the value.
+func (Redacted) LogValue() slog.Value {
+	return slog.StringValue(redactedValue)
+}
+
+// String hides the value when it is formatted with fmt.
+func (Redacted) String() string {
+	return redactedValue
+}
+
+func (r *redactor) enabled() bool {
+	return len(r.keys) > 0 || len(r.keyPatterns) > 0 || len(r.valuePatterns) > 0 || r.cardNumbers
+}
+
+// replaceAttr is a slog.HandlerOptions.ReplaceAttr redacting a.
+//
+// The whole value is redacted when its key, or the name of one of its groups, is sensitive.
+// Otherwise, the parts of string values matching a value pattern are redacted, and maps,
+// slices and structs are walked to redact their entries the same way, see redactValue.
+func (r *redactor) replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if r.sensitiveKey(a.Key) || slices.ContainsFunc(groups, r.sensitiveKey) {
+		return slog.String(a.Key, redactedValue)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if s, ok := r.redactString(a.Value.String()); ok {
+			return slog.String(a.Key, s)
+		}
+	case slog.KindAny:
+		if v, ok := r.redactValue(reflect.ValueOf(a.Value.Any()), 0); ok {
+			return slog.Any(a.Key, v)
+		}
+	}
+
+	return a
+}
+
+// redactValue redacts v and reports whether it changed, the unchanged values are logged as is.
+//
+// Errors and fmt.Stringer values are formatted to be matched by the value patterns.
+// Maps and structs are copied to a map[string]any, with the entries having a sensitive key
+// or exported field name (its JSON name if tagged) redacted, and slices to a []any.
+func (r *redactor) redactValue(v reflect.Value, depth int) (any, bool) {
+	if !v.IsValid() || depth > maxRedactDepth {
+		return nil, false
+	}
+
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return nil, false
+	}
+
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case error:
+			return r.redactString(x.Error())
+		case fmt.Stringer:
+			return r.redactString(x.String())
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return r.redactString(v.String())
+	case reflect.Pointer, reflect.Interface:
+		return r.redactValue(v.Elem(), depth+1)
+	case reflect.Map:
+		return r.redactMap(v, depth)
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return nil, false
+		}
+
+		return r.redactSlice(v, depth)
+	case reflect.Struct:
+		return r.redactStruct(v, depth)
+	default:
+		return nil, false
+	}
+}
+
+func (r *redactor) redactMap(v reflect.Value, depth int) (any, bool) {
+	out := make(map[string]any, v.Len())
+	changed := false
+
+	iter := v.MapRange()
+	for iter.Next() {
+		key := fmt.Sprint(iter.Key().Interface())
+		changed = r.redactEntry(out, key, iter.Value(), depth) || changed
+	}
+
+	return out, changed
+}
+
+func (r *redactor) redactSlice(v reflect.Value, depth int) (any, bool) {
+	out := make([]any, v.Len())
+	changed := false
+
+	for i := range v.Len() {
+		elem, ok := r.redactValue(v.Index(i), depth+1)
+		if !ok {
+			elem = v.Index(i).Interface()
+		}
+
+		out[i] = elem
+		changed = ok || changed
+	}
+
+	return out, changed
+}
+
+func (r *redactor) redactStruct(v reflect.Value, depth int) (any, bool) {
+	out := make(map[string]any, v.NumField())
+	changed := false
+
+	for i := range v.NumField() {
+		f := v.Type().Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		changed = r.redactEntry(out, name, v.Field(i), depth) || changed
+	}
+
+	return out, changed
+}
+
+// redactEntry sets the entry key of out to v, redacted, and reports whether it was redacted.
+func (r *redactor) redactEntry(out map[string]any, key string, v reflect.Value, depth int) bool {
+	if r.sensitiveKey(key) {
+		out[key] = redactedValue
+		return true
+	}
+
+	redacted, ok := r.redactValue(v, depth+1)
+	if !ok {
+		redacted = v.Interface()
+	}
+
+	out[key] = redacted
+
+	return ok
+}
+
+// redactString redacts the parts of s matching a value pattern, and reports whether s changed.
+func (r *redactor) redactString(s string) (string, bool) {
+	redacted := s
+	for _, p := range r.valuePatterns {
+		redacted = p.ReplaceAllString(redacted, redactedValue)
+	}
+
+	if r.cardNumbers {
+		redacted = CardNumberPattern.ReplaceAllStringFunc(redacted, redactCardNumber)
+	}
+
+	return redacted, redacted != s
+}
+
+func (r *redactor) sensitiveKey(key string) bool {
+	for _, k := range r.keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	for _, p := range r.keyPatterns {
+		if p.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactCardNumber redacts s, a match of CardNumberPattern, when it is a valid card number.
+func redactCardNumber(s string) string {
+	if !luhnValid(s) {
+		return s
+	}
+
+	return redactedValue
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum, ignoring the other characters.
+func luhnValid(s string) bool {
+	var sum, n int
+
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		n++
+	}
+
+	return n > 0 && sum%10 == 0
+}
@@ -0,0 +1,438 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// backupTimeFormat names the backups, it sorts them from the oldest to the newest.
+const backupTimeFormat = "20060102T150405.000000000"
+
+type (
+	// RotateOption configures a RotatingFile, see NewRotatingFile.
+	RotateOption interface {
+		applyRotate(*RotatingFile)
+	}
+
+	rotateOptionFunc func(*RotatingFile)
+
+	// RotatingFile is an io.Writer appending to a file that is rotated by size and/or time,
+	// to be used with WithWriter. It is safe for concurrent writes.
+	//
+	// A rotated file is renamed to a backup next to it, suffixed with the time of the rotation
+	// like app.log.20240102T150405.000000000, and a new file is opened at the path.
+	//
+	// The file is reopened on SIGHUP, for compatibility with logrotate and its create mode.
+	RotatingFile struct {
+		path       string
+		maxSize    int64
+		interval   time.Duration
+		maxBackups int
+		compress   bool
+		onError    func(error)
+		now        func() time.Time
+
+		mu sync.Mutex
+		// file is nil when it could not be opened again, the next write retries.
+		file     *os.File
+		closed   bool
+		size     int64
+		openedAt time.Time
+
+		// millMu serializes the compression and the removal of the backups.
+		millMu sync.Mutex
+		wg     sync.WaitGroup
+
+		signals chan os.Signal
+		done    chan struct{}
+	}
+)
+
+func (fn rotateOptionFunc) applyRotate(f *RotatingFile) {
+	fn(f)
+}
+
+// WithMaxSize rotates the file before a write makes it larger than size bytes.
+func WithMaxSize(size int64) RotateOption {
+	return rotateOptionFunc(func(f *RotatingFile) {
+		f.maxSize = size
+	})
+}
+
+// WithRotateInterval rotates the file on the first write once it has been open for interval.
+func WithRotateInterval(interval time.Duration) RotateOption {
+	return rotateOptionFunc(func(f *RotatingFile) {
+		f.interval = interval
+	})
+}
+
+// WithMaxBackups keeps the n most recent backups and removes the older ones, all are kept by default.
+func WithMaxBackups(n int) RotateOption {
+	return rotateOptionFunc(func(f *RotatingFile) {
+		f.maxBackups = n
+	})
+}
+
+// WithCompress compresses the backups with gzip, adding a .gz extension.
+func WithCompress() RotateOption {
+	return rotateOptionFunc(func(f *RotatingFile) {
+		f.compress = true
+	})
+}
+
+// WithRotateErrorHandler sets the function called with the errors that Write can't return:
+// the failed renames of a file that is still written to, and the errors happening in
+// the background while reopening the file on SIGHUP or compressing and removing the backups.
+// These errors are ignored by default.
+func WithRotateErrorHandler(fn func(error)) RotateOption {
+	return rotateOptionFunc(func(f *RotatingFile) {
+		f.onError = fn
+	})
+}
+
+// NewRotatingFile opens the file at path for appending, creating it and its directory if needed.
+// Without WithMaxSize or WithRotateInterval, the file is never rotated.
+//
+// Close must be called to stop listening for SIGHUP and to wait for the backups to be compressed.
+func NewRotatingFile(path string, opts ...RotateOption) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:    path,
+		onError: func(error) {},
+		now:     time.Now,
+		signals: make(chan os.Signal, 1),
+		done:    make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt.applyRotate(f)
+	}
+
+	if f.onError == nil {
+		f.onError = func(error) {}
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("error creating log directory: %w", err)
+	}
+
+	err = f.open()
+	if err != nil {
+		return nil, err
+	}
+
+	signal.Notify(f.signals, syscall.SIGHUP)
+
+	f.wg.Add(1)
+	go f.reopenOnSignal()
+
+	return f, nil
+}
+
+// Write writes p to the file, rotating it first if needed.
+// When the file could not be opened again after a rotation or a reopen, Write opens it first.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+
+	if f.file == nil {
+		err := f.open()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if f.size > 0 && f.shouldRotate(int64(len(p))) {
+		err := f.rotate()
+		if err != nil {
+			if f.file == nil {
+				return 0, err
+			}
+
+			// The file could not be renamed but is still open, keep the record.
+			f.onError(err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, err
+}
+
+// Rotate rotates the file now.
+func (f *RotatingFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return os.ErrClosed
+	}
+
+	return f.rotate()
+}
+
+// Reopen closes the file and opens the file at path again, e.g. after it has been moved by logrotate.
+func (f *RotatingFile) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return os.ErrClosed
+	}
+
+	err := f.closeFile()
+	if err != nil {
+		return err
+	}
+
+	return f.open()
+}
+
+// Close stops listening for SIGHUP, waits for the backups to be compressed and closes the file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		return os.ErrClosed
+	}
+
+	f.closed = true
+	signal.Stop(f.signals)
+	close(f.done)
+
+	err := f.closeFile()
+	f.mu.Unlock()
+
+	f.wg.Wait()
+
+	return err
+}
+
+func (f *RotatingFile) reopenOnSignal() {
+	defer f.wg.Done()
+
+	for {
+		select {
+		case <-f.signals:
+			err := f.Reopen()
+			if err != nil && !errors.Is(err, os.ErrClosed) {
+				f.onError(fmt.Errorf("error reopening log file %s: %w", f.path, err))
+			}
+		case <-f.done:
+			return
+		}
+	}
+}
+
+// shouldRotate reports whether the file must be rotated before writing n bytes.
+// It must be called with the lock held.
+func (f *RotatingFile) shouldRotate(n int64) bool {
+	if f.maxSize > 0 && f.size+n > f.maxSize {
+		return true
+	}
+
+	return f.interval > 0 && f.now().Sub(f.openedAt) >= f.interval
+}
+
+// open opens the file at path, it must be called with the lock held.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("error opening log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+
+	return nil
+}
+
+// closeFile closes the file if it is open, it must be called with the lock held.
+// The file is forgotten even when closing it fails.
+func (f *RotatingFile) closeFile() error {
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	if err != nil {
+		return fmt.Errorf("error closing log file: %w", err)
+	}
+
+	return nil
+}
+
+// rotate renames the file to a backup and opens a new one, it must be called with the lock held.
+// The backups are compressed and removed in the background.
+func (f *RotatingFile) rotate() error {
+	err := f.closeFile()
+	if err != nil {
+		return err
+	}
+
+	backup := f.path + "." + f.now().UTC().Format(backupTimeFormat)
+
+	err = os.Rename(f.path, backup)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		// Keep writing to the current file rather than losing the records.
+		return errors.Join(fmt.Errorf("error renaming log file: %w", err), f.open())
+	}
+
+	err = f.open()
+	if err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+
+		err := f.mill()
+		if err != nil {
+			f.onError(fmt.Errorf("error processing log backups of %s: %w", f.path, err))
+		}
+	}()
+
+	return nil
+}
+
+// mill compresses the backups if needed and removes the ones exceeding maxBackups.
+func (f *RotatingFile) mill() error {
+	f.millMu.Lock()
+	defer f.millMu.Unlock()
+
+	backups, err := f.backups()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	if f.compress {
+		for i, backup := range backups {
+			if strings.HasSuffix(backup, ".gz") {
+				continue
+			}
+
+			err = compressFile(backup)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			backups[i] = backup + ".gz"
+		}
+	}
+
+	if f.maxBackups > 0 && len(backups) > f.maxBackups {
+		for _, backup := range backups[:len(backups)-f.maxBackups] {
+			err = os.Remove(backup)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, fmt.Errorf("error removing log backup: %w", err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// backups returns the paths of the backups, from the oldest to the newest.
+func (f *RotatingFile) backups() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading log directory: %w", err)
+	}
+
+	prefix := filepath.Base(f.path) + "."
+
+	var backups []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		ts := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".gz")
+		if _, err := time.Parse(backupTimeFormat, ts); err != nil {
+			continue
+		}
+
+		backups = append(backups, filepath.Join(filepath.Dir(f.path), name))
+	}
+
+	// The timestamps sort in time order, ignoring the .gz extension.
+	slices.SortFunc(backups, func(a, b string) int {
+		return strings.Compare(strings.TrimSuffix(a, ".gz"), strings.TrimSuffix(b, ".gz"))
+	})
+
+	return backups, nil
+}
+
+// compressFile replaces the file at path with a gzip-compressed copy at path.gz.
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening log backup: %w", err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("error creating compressed log backup: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			_ = dst.Close()
+			_ = os.Remove(path + ".gz")
+		}
+	}()
+
+	gz := gzip.NewWriter(dst)
+
+	_, err = io.Copy(gz, src)
+	if err != nil {
+		return fmt.Errorf("error compressing log backup: %w", err)
+	}
+
+	err = gz.Close()
+	if err != nil {
+		return fmt.Errorf("error compressing log backup: %w", err)
+	}
+
+	err = dst.Close()
+	if err != nil {
+		return fmt.Errorf("error compressing log backup: %w", err)
+	}
+
+	err = os.Remove(path)
+	if err != nil {
+		return fmt.Errorf("error removing compressed log backup: %w", err)
+	}
+
+	return nil
+}
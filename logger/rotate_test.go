@@ -0,0 +1,198 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	return string(b)
+}
+
+func TestRotatingFile_size(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+
+	f, err := NewRotatingFile(path, WithMaxSize(10), WithMaxBackups(2))
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err = f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, "fourth\n", readFile(t, path))
+
+	backups, err := f.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, "second\n", readFile(t, backups[0]))
+	assert.Equal(t, "third\n", readFile(t, backups[1]))
+}
+
+func TestRotatingFile_interval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	f, err := NewRotatingFile(path, WithRotateInterval(time.Hour))
+	require.NoError(t, err)
+	f.now = func() time.Time { return now }
+	f.openedAt = now
+
+	_, err = f.Write([]byte("before\n"))
+	require.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	_, err = f.Write([]byte("after\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, "after\n", readFile(t, path))
+	assert.Equal(t, "before\n", readFile(t, path+".20240101T010000.000000000"))
+}
+
+func TestRotatingFile_compress(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := NewRotatingFile(path, WithCompress())
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("compressed\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Rotate())
+	require.NoError(t, f.Close())
+
+	backups, err := f.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	require.True(t, strings.HasSuffix(backups[0], ".gz"))
+
+	file, err := os.Open(backups[0])
+	require.NoError(t, err)
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, "compressed\n", string(b))
+}
+
+func TestRotatingFile_reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := NewRotatingFile(path)
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("old\n"))
+	require.NoError(t, err)
+
+	// Like logrotate, move the file away then ask for it to be reopened.
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, f.Reopen())
+
+	_, err = f.Write([]byte("new\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	assert.Equal(t, "old\n", readFile(t, path+".1"))
+	assert.Equal(t, "new\n", readFile(t, path))
+
+	_, err = f.Write([]byte("closed\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestRotatingFile_reopenFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	errs := make(chan error, 1)
+	f, err := NewRotatingFile(path, WithRotateErrorHandler(func(err error) { errs <- err }))
+	require.NoError(t, err)
+	defer f.Close()
+
+	// A directory in place of the file makes reopening it fail.
+	require.NoError(t, os.Rename(path, path+".1"))
+	require.NoError(t, os.Mkdir(path, 0o755))
+
+	err = f.Reopen()
+	require.ErrorContains(t, err, "error opening log file")
+
+	_, err = f.Write([]byte("lost\n"))
+	require.ErrorContains(t, err, "error opening log file")
+	assert.NotErrorIs(t, err, os.ErrClosed)
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+
+	if process.Signal(syscall.SIGHUP) == nil {
+		select {
+		case err := <-errs:
+			assert.ErrorContains(t, err, "error reopening log file")
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the failed reopen on SIGHUP to be reported")
+		}
+	}
+
+	// The next write opens the file once it can.
+	require.NoError(t, os.Remove(path))
+
+	_, err = f.Write([]byte("written\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "written\n", readFile(t, path))
+}
+
+func TestRotatingFile_concurrentWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := NewRotatingFile(path, WithMaxSize(100))
+	require.NoError(t, err)
+
+	log := New(WithWriter(f), WithFormat(FormatText))
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := range 10 {
+				log.Info(fmt.Sprintf("record %d-%d", i, j))
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, f.Close())
+
+	backups, err := f.backups()
+	require.NoError(t, err)
+
+	var lines int
+	for _, p := range append(backups, path) {
+		content := readFile(t, p)
+		lines += strings.Count(content, "\n")
+
+		// Every record is written whole to a single file.
+		for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+			assert.Contains(t, line, "msg=\"record ")
+		}
+	}
+
+	assert.Equal(t, 100, lines)
+}
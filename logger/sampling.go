@@ -0,0 +1,196 @@
+package logger
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pushkar-anand/build-with-go/ctxval"
+)
+
+type (
+	// sampler counts the records by level and message, in windows of interval.
+	// It is shared by a samplingHandler and all the handlers derived from it.
+	//
+	// The summaries of a window are emitted by the first record of the next window or, when no
+	// record follows, by a timer started with the first suppressed record of the window.
+	sampler struct {
+		first      int
+		thereafter int
+		interval   time.Duration
+		now        func() time.Time
+
+		// root receives the summaries of the suppressed records, without the attributes
+		// of the logger that logged them.
+		root slog.Handler
+
+		mu          sync.Mutex
+		windowStart time.Time
+		counts      map[sampleKey]*sampleCount
+		// timer flushes the summaries at the end of the window, nil when nothing is suppressed.
+		timer *time.Timer
+	}
+
+	sampleKey struct {
+		level   slog.Level
+		message string
+	}
+
+	sampleCount struct {
+		seen       int
+		suppressed int
+	}
+
+	// samplingHandler drops the records exceeding the sampling rate of their level and message.
+	samplingHandler struct {
+		slog.Handler
+		sampler *sampler
+	}
+)
+
+func newSamplingHandler(h slog.Handler, first, thereafter int, interval time.Duration) *samplingHandler {
+	return &samplingHandler{
+		Handler: h,
+		sampler: &sampler{
+			first:      first,
+			thereafter: thereafter,
+			interval:   interval,
+			now:        time.Now,
+			root:       h,
+			counts:     make(map[sampleKey]*sampleCount),
+		},
+	}
+}
+
+// Handle passes r to the underlying handler if it is sampled.
+// Records logged with a context flagged by ctxval.WithDebug are always passed.
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctxval.DebugFromContext(ctx) {
+		return h.Handler.Handle(ctx, r)
+	}
+
+	sampled, summaries := h.sampler.sample(sampleKey{level: r.Level, message: r.Message})
+
+	for _, s := range summaries {
+		// A failed summary must not drop the record itself.
+		_ = h.sampler.root.Handle(ctx, s)
+	}
+
+	if !sampled {
+		return nil
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs returns a new handler with the given attributes, sharing the counts of h.
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithAttrs(attrs), sampler: h.sampler}
+}
+
+// WithGroup returns a new handler with the given group, sharing the counts of h.
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithGroup(name), sampler: h.sampler}
+}
+
+// sample counts a record with key and reports whether it is sampled: the first records of
+// each window are, then one in thereafter. When a window ends, it returns the summaries
+// of the records suppressed during that window.
+func (s *sampler) sample(key sampleKey) (bool, []slog.Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	summaries := s.rollover(now)
+
+	c, ok := s.counts[key]
+	if !ok {
+		c = &sampleCount{}
+		s.counts[key] = c
+	}
+
+	c.seen++
+
+	if c.seen <= s.first || (s.thereafter > 0 && (c.seen-s.first)%s.thereafter == 0) {
+		return true, summaries
+	}
+
+	c.suppressed++
+
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.windowStart.Add(s.interval).Sub(now), s.flush)
+	}
+
+	return false, summaries
+}
+
+// flush emits the summaries of the current window once it has ended, it is called by the timer.
+func (s *sampler) flush() {
+	s.mu.Lock()
+
+	s.timer = nil
+	now := s.now()
+
+	summaries := s.rollover(now)
+	if summaries == nil && len(s.counts) > 0 {
+		// The window has not ended yet, wait for its end.
+		s.timer = time.AfterFunc(s.windowStart.Add(s.interval).Sub(now), s.flush)
+	}
+
+	s.mu.Unlock()
+
+	for _, r := range summaries {
+		_ = s.root.Handle(context.Background(), r)
+	}
+}
+
+// rollover starts a new window when the current one has ended at now and returns the summaries
+// of the ended window, nil when it has not ended. It must be called with the lock held.
+func (s *sampler) rollover(now time.Time) []slog.Record {
+	if now.Sub(s.windowStart) < s.interval {
+		return nil
+	}
+
+	summaries := s.summaries(now)
+	s.windowStart = now
+	clear(s.counts)
+
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+
+	return summaries
+}
+
+// summaries returns a record for every key with suppressed records in the current window,
+// ordered by level and message.
+func (s *sampler) summaries(now time.Time) []slog.Record {
+	var keys []sampleKey
+	for key, c := range s.counts {
+		if c.suppressed > 0 {
+			keys = append(keys, key)
+		}
+	}
+
+	slices.SortFunc(keys, func(a, b sampleKey) int {
+		return cmp.Or(cmp.Compare(a.level, b.level), cmp.Compare(a.message, b.message))
+	})
+
+	summaries := make([]slog.Record, 0, len(keys))
+	for _, key := range keys {
+		r := slog.NewRecord(now, key.level, "suppressed log records", 0)
+		r.AddAttrs(
+			slog.String("message", key.message),
+			slog.Int("suppressed", s.counts[key].suppressed),
+			slog.Duration("interval", s.interval),
+		)
+		summaries = append(summaries, r)
+	}
+
+	return summaries
+}
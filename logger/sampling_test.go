@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pushkar-anand/build-with-go/ctxval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSampler(buf *bytes.Buffer, first, thereafter int) (*slog.Logger, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	h := newSamplingHandler(slog.NewJSONHandler(buf, nil), first, thereafter, time.Hour)
+	h.sampler.now = func() time.Time { return now }
+
+	return slog.New(h), &now
+}
+
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+
+		var r map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &r))
+		records = append(records, r)
+	}
+
+	return records
+}
+
+func TestSampling(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := newTestSampler(&buf, 2, 3)
+
+	for i := range 10 {
+		log.Info("tick", "i", i)
+	}
+	log.Info("other")
+
+	var got []float64
+	for _, r := range decodeRecords(t, &buf) {
+		if r["msg"] == "tick" {
+			got = append(got, r["i"].(float64))
+		}
+	}
+
+	// The first 2, then 1 in 3.
+	assert.Equal(t, []float64{0, 1, 4, 7}, got)
+	assert.Contains(t, buf.String(), `"msg":"other"`)
+}
+
+func TestSampling_levels(t *testing.T) {
+	var buf bytes.Buffer
+	log, _ := newTestSampler(&buf, 1, 0)
+
+	log.Info("same")
+	log.Warn("same")
+	log.Info("same")
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 2)
+	assert.Equal(t, "INFO", records[0]["level"])
+	assert.Equal(t, "WARN", records[1]["level"])
+}
+
+func TestSampling_summary(t *testing.T) {
+	var buf bytes.Buffer
+	log, now := newTestSampler(&buf, 1, 0)
+
+	log = log.With("component", "worker")
+	for range 5 {
+		log.Info("tick")
+	}
+	log.Warn("alert")
+
+	*now = now.Add(time.Hour)
+	buf.Reset()
+	log.Info("tick")
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 2)
+
+	summary := records[0]
+	assert.Equal(t, "suppressed log records", summary["msg"])
+	assert.Equal(t, "INFO", summary["level"])
+	assert.Equal(t, "tick", summary["message"])
+	assert.Equal(t, float64(4), summary["suppressed"])
+	assert.NotContains(t, summary, "component")
+
+	// The counts start over in the new window.
+	assert.Equal(t, "tick", records[1]["msg"])
+	assert.Equal(t, "worker", records[1]["component"])
+}
+
+// lockedBuffer is a bytes.Buffer safe for the writes of the summary timer.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestSampling_summaryWithoutFollowingRecord(t *testing.T) {
+	var buf lockedBuffer
+	log := New(WithWriter(&buf), WithFormat(FormatJSON), WithSampling(1, 0, 20*time.Millisecond))
+
+	for range 5 {
+		log.Info("burst")
+	}
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), `"msg":"suppressed log records"`)
+	}, 5*time.Second, 5*time.Millisecond)
+
+	assert.Contains(t, buf.String(), `"message":"burst","suppressed":4`)
+	assert.Equal(t, 1, strings.Count(buf.String(), `"msg":"burst"`))
+}
+
+func TestSampling_debugContext(t *testing.T) {
+	var buf bytes.Buffer
+	log, now := newTestSampler(&buf, 1, 0)
+
+	ctx := ctxval.WithDebug(context.Background(), true)
+	for range 3 {
+		log.InfoContext(ctx, "tick")
+	}
+
+	assert.Len(t, decodeRecords(t, &buf), 3)
+
+	// Debug records are not counted as suppressed.
+	*now = now.Add(time.Hour)
+	log.Info("tick")
+	assert.NotContains(t, buf.String(), "suppressed")
+}
+
+func TestNew_withSampling(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(WithWriter(&buf), WithFormat(FormatJSON), WithSampling(1, 0, time.Minute))
+
+	ctx := ctxval.WithRequestID(context.Background(), "req-1")
+	log.InfoContext(ctx, "tick")
+	log.InfoContext(ctx, "tick")
+
+	records := decodeRecords(t, &buf)
+	require.Len(t, records, 1)
+	assert.Equal(t, "req-1", records[0]["request_id"])
+}
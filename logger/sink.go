@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+)
+
+// multiHandler passes every record to each of its handlers enabled at the level of the record.
+type multiHandler struct {
+	handlers []slog.Handler
+}
+
+// Enabled reports whether one of the handlers is enabled at level.
+func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handle passes r to every handler enabled at its level, a failing handler does not stop the others.
+func (h *multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, r.Level) {
+			continue
+		}
+
+		err := handler.Handle(ctx, r.Clone())
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a new handler with the given attributes added to every handler.
+func (h *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithAttrs(attrs)
+	}
+
+	return &multiHandler{handlers: handlers}
+}
+
+// WithGroup returns a new handler with the given group added to every handler.
+func (h *multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(h.handlers))
+	for i, handler := range h.handlers {
+		handlers[i] = handler.WithGroup(name)
+	}
+
+	return &multiHandler{handlers: handlers}
+}
+
+// sinkConfig returns the config of a sink configured with opts. The sink inherits the caller
+// and the redaction of the logger, and logs every level allowed by the logger by default.
+func (c *config) sinkConfig(opts []Option) *config {
+	sc := defaultConfig()
+	sc.level = minLevel
+	sc.addCaller = c.addCaller
+	sc.redactor = redactor{
+		keys:          slices.Clone(c.redactor.keys),
+		keyPatterns:   slices.Clone(c.redactor.keyPatterns),
+		valuePatterns: slices.Clone(c.redactor.valuePatterns),
+		cardNumbers:   c.redactor.cardNumbers,
+	}
+
+	for _, opt := range opts {
+		opt.apply(sc)
+	}
+
+	return sc
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/pushkar-anand/build-with-go/ctxval"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_withSinks(t *testing.T) {
+	var stdout, file bytes.Buffer
+
+	log := New(
+		WithLevel(slog.LevelInfo),
+		WithRedactKeys("password"),
+		WithSink(WithWriter(&stdout), WithFormat(FormatJSON)),
+		WithSink(WithWriter(&file), WithLevel(slog.LevelWarn), WithRedactKeys("email")),
+	)
+
+	ctx := ctxval.WithRequestID(context.Background(), "req-1")
+	log = log.With("component", "api")
+
+	log.DebugContext(ctx, "debug")
+	log.InfoContext(ctx, "info", "email", "user@example.com")
+	log.WarnContext(ctx, "warn", "password", "hunter2", "email", "user@example.com")
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var info map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &info))
+	assert.Equal(t, "info", info["msg"])
+	assert.Equal(t, "req-1", info["request_id"])
+	assert.Equal(t, "api", info["component"])
+	assert.Equal(t, "user@example.com", info["email"])
+	assert.NotContains(t, stdout.String(), "hunter2")
+
+	out := file.String()
+	assert.NotContains(t, out, "msg=info")
+	assert.Contains(t, out, "msg=warn")
+	assert.Contains(t, out, "request_id=req-1")
+	assert.Contains(t, out, "component=api")
+	assert.Contains(t, out, "password=[REDACTED]")
+	assert.Contains(t, out, "email=[REDACTED]")
+}
+
+func TestNew_withSinksAndLevels(t *testing.T) {
+	var debug, warn bytes.Buffer
+
+	levels := NewLevels(slog.LevelInfo)
+	log := New(
+		WithLevels(levels),
+		WithSink(WithWriter(&debug)),
+		WithSink(WithWriter(&warn), WithLevel(slog.LevelWarn)),
+	)
+
+	log.Debug("hidden")
+	log.Info("info")
+	assert.NotContains(t, debug.String(), "hidden")
+	assert.Contains(t, debug.String(), "info")
+	assert.Empty(t, warn.String())
+
+	levels.Set("", slog.LevelDebug, 0)
+	log.Debug("shown")
+	assert.Contains(t, debug.String(), "shown")
+	assert.Empty(t, warn.String())
+}
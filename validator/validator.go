@@ -22,6 +22,8 @@ type (
 		Value   any    `json:"value"`
 		Rule    string `json:"rule"`
 		Message string `json:"message"`
+		// Path is the Go path of the field in the validated struct, e.g. "Server.Port".
+		Path string `json:"-"`
 	}
 
 	Result struct {
@@ -122,6 +124,7 @@ func (v *Validator) parseError(err error) (*Result, error) {
 				Value:   validationErr.Value(),
 				Rule:    tag,
 				Message: v.createUserFriendlyMessage(field, tag, validationErr),
+				Path:    trimRootNamespace(validationErr.StructNamespace()),
 			}
 		}
 
@@ -130,3 +133,14 @@ func (v *Validator) parseError(err error) (*Result, error) {
 		return nil, fmt.Errorf("validation failed with unexpected error: %w", err)
 	}
 }
+
+// trimRootNamespace removes the name of the validated struct from a field namespace,
+// "User.Address.Street" becomes "Address.Street".
+func trimRootNamespace(ns string) string {
+	_, field, found := strings.Cut(ns, ".")
+	if !found {
+		return ns
+	}
+
+	return field
+}
@@ -271,3 +271,48 @@ func resetValidator() {
 	vErr = nil
 	once = sync.Once{}
 }
+
+// TestValidator_ValidateStruct_nested tests that nested fields report their path in the struct
+func TestValidator_ValidateStruct_nested(t *testing.T) {
+	resetValidator()
+
+	type (
+		Address struct {
+			Street string `json:"street" validate:"required"`
+		}
+
+		Server struct {
+			Port int `validate:"min=1"`
+		}
+
+		NestedStruct struct {
+			Name     string  `json:"name" validate:"required"`
+			Home     Address `json:"home"`
+			Server   Server
+			Untagged string `validate:"required"`
+		}
+	)
+
+	v, err := New()
+	require.NoError(t, err)
+
+	result, err := v.ValidateStruct(context.Background(), NestedStruct{})
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+
+	expected := map[string]string{
+		"name":     "Name",
+		"street":   "Home.Street",
+		"Port":     "Server.Port",
+		"Untagged": "Untagged",
+	}
+
+	require.Len(t, result.Failed, len(expected))
+	for field, path := range expected {
+		reason, exists := result.Failed[field]
+		require.True(t, exists, "Field %s should have an error", field)
+		assert.Equal(t, path, reason.Path)
+	}
+
+	assert.Equal(t, "street is required", result.Failed["street"].Message)
+}